@@ -10,7 +10,7 @@ import (
 )
 
 // @Summary Create a new agent
-// @Description Create a new agent with name and password
+// @Description Redeem an invitation token and create a new agent with name and password. Invited agents start with the analyst role.
 // @Tags agents
 // @Accept json
 // @Produce json
@@ -18,12 +18,14 @@ import (
 // @Success 201 {object} AgentResponseDoc
 // @Failure 400 {object} ErrorResponseDoc
 // @Failure 422 {object} ValidationErrorResponseDoc
+// @Failure 429 {object} ErrorResponseDoc
 // @Failure 500 {object} ErrorResponseDoc
 // @Router /agents [post]
 func (app *application) createAgentHandler(w http.ResponseWriter, r *http.Request) {
 	var input struct {
-		Name     string
-		Password string
+		Token    string `json:"token"`
+		Name     string `json:"name"`
+		Password string `json:"password"`
 	}
 
 	err := app.readJSON(w, r, &input)
@@ -32,8 +34,15 @@ func (app *application) createAgentHandler(w http.ResponseWriter, r *http.Reques
 		return
 	}
 
+	v := validator.New()
+	if model.ValidateTokenPlaintext(v, input.Token); !v.Valid() {
+		app.failedValidationResponse(w, r, v.Errors)
+		return
+	}
+
 	agent := &model.Agent{
 		Name: input.Name,
+		Role: model.RoleAnalyst,
 	}
 
 	err = agent.Password.Set(input.Password)
@@ -42,15 +51,17 @@ func (app *application) createAgentHandler(w http.ResponseWriter, r *http.Reques
 		return
 	}
 
-	v := validator.New()
 	if model.ValidateAgent(v, agent); !v.Valid() {
 		app.failedValidationResponse(w, r, v.Errors)
 		return
 	}
 
-	err = app.agentsService.Create(r.Context(), agent)
+	err = app.tokensService.RedeemInvitation(r.Context(), input.Token, agent)
 	if err != nil {
 		switch {
+		case errors.Is(err, storage.ErrorModelNotFound):
+			v.AddError("token", "invalid or expired invitation token")
+			app.failedValidationResponse(w, r, v.Errors)
 		case errors.Is(err, storage.ErrorUniqueConstraintViolation):
 			v.AddError("name", "an agent with this name is already exists")
 			app.failedValidationResponse(w, r, v.Errors)
@@ -65,3 +76,128 @@ func (app *application) createAgentHandler(w http.ResponseWriter, r *http.Reques
 		app.serverErrorResponse(w, r, err)
 	}
 }
+
+// @Summary Revoke agent tokens
+// @Description Revoke all tokens issued to a specific agent
+// @Tags agents
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Agent ID"
+// @Success 200 {object} MessageResponseDoc
+// @Failure 401 {object} ErrorResponseDoc
+// @Failure 403 {object} ErrorResponseDoc
+// @Failure 404 {object} ErrorResponseDoc
+// @Failure 500 {object} ErrorResponseDoc
+// @Router /agents/{id}/tokens [delete]
+func (app *application) revokeAgentTokensHandler(w http.ResponseWriter, r *http.Request) {
+	id, err := app.readIDParam(r, "id")
+	if err != nil {
+		app.notFoundResponse(w, r)
+		return
+	}
+
+	agent, err := app.agentsService.GetById(r.Context(), id)
+	if err != nil {
+		switch {
+		case errors.Is(err, storage.ErrorModelNotFound):
+			app.notFoundResponse(w, r)
+		default:
+			app.serverErrorResponse(w, r, err)
+		}
+		return
+	}
+
+	err = app.tokensService.RevokeAllForUser(r.Context(), agent.Id, model.AgentUserType)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+		return
+	}
+
+	err = app.writeJson(w, http.StatusOK, envelope{"message": "agent tokens successfully revoked"})
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+	}
+}
+
+// @Summary Change agent role
+// @Description Change the role, and so the permissions, of a specific agent
+// @Tags agents
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Agent ID"
+// @Param role body ChangeAgentRoleRequestDoc true "Agent Role"
+// @Success 200 {object} AgentResponseDoc
+// @Failure 400 {object} ErrorResponseDoc
+// @Failure 401 {object} ErrorResponseDoc
+// @Failure 403 {object} ErrorResponseDoc
+// @Failure 404 {object} ErrorResponseDoc
+// @Failure 422 {object} ValidationErrorResponseDoc
+// @Failure 500 {object} ErrorResponseDoc
+// @Router /agents/{id}/role [put]
+func (app *application) changeAgentRoleHandler(w http.ResponseWriter, r *http.Request) {
+	id, err := app.readIDParam(r, "id")
+	if err != nil {
+		app.notFoundResponse(w, r)
+		return
+	}
+
+	var input struct {
+		Role model.Role `json:"role"`
+	}
+
+	err = app.readJSON(w, r, &input)
+	if err != nil {
+		app.badRequestResponse(w, r, err)
+		return
+	}
+
+	v := validator.New()
+	if model.ValidateRole(v, input.Role); !v.Valid() {
+		app.failedValidationResponse(w, r, v.Errors)
+		return
+	}
+
+	agent, err := app.agentsService.ChangeRole(r.Context(), id, input.Role)
+	if err != nil {
+		switch {
+		case errors.Is(err, storage.ErrorModelNotFound):
+			app.notFoundResponse(w, r)
+		default:
+			app.serverErrorResponse(w, r, err)
+		}
+		return
+	}
+
+	err = app.writeJson(w, http.StatusOK, envelope{"agent": agent})
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+	}
+}
+
+// @Summary Create an agent invitation
+// @Description Create a single-use invitation token which allows a new agent to register
+// @Tags agents
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Success 201 {object} InvitationTokenResponseDoc
+// @Failure 401 {object} ErrorResponseDoc
+// @Failure 403 {object} ErrorResponseDoc
+// @Failure 500 {object} ErrorResponseDoc
+// @Router /tokens/invitation [post]
+func (app *application) createAgentInvitationHandler(w http.ResponseWriter, r *http.Request) {
+	agent := app.contextGetAgent(r)
+
+	token, err := app.tokensService.Create(r.Context(), agent.Id, model.InvitationUserType, app.config.tokens.invitationTtl, model.ScopeInvitation)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+		return
+	}
+
+	err = app.writeJson(w, http.StatusCreated, envelope{"invitation_token": token})
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+	}
+}
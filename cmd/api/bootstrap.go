@@ -0,0 +1,42 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/m1crogravity/spy-cat-agency/internal/model"
+	"github.com/m1crogravity/spy-cat-agency/internal/validator"
+)
+
+// bootstrapAdmin creates the very first agent with the admin role. It refuses
+// to do anything once the agents table has any rows, so further agents have
+// to be invited.
+func (app *application) bootstrapAdmin(name, password string) error {
+	agent := &model.Agent{
+		Name: name,
+		Role: model.RoleAdmin,
+	}
+
+	err := agent.Password.Set(password)
+	if err != nil {
+		return err
+	}
+
+	v := validator.New()
+	if model.ValidateAgent(v, agent); !v.Valid() {
+		return fmt.Errorf("invalid bootstrap agent: %v", v.Errors)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	err = app.agentsService.Bootstrap(ctx, agent)
+	if err != nil {
+		return err
+	}
+
+	app.logger.Info("bootstrapped admin agent", "id", agent.Id, "name", agent.Name)
+
+	return nil
+}
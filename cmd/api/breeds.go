@@ -0,0 +1,63 @@
+package main
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/julienschmidt/httprouter"
+	"github.com/m1crogravity/spy-cat-agency/internal/storage"
+)
+
+// @Summary List breeds
+// @Description Get the breeds a spy cat can be of, optionally filtered by a case-insensitive name prefix
+// @Tags breeds
+// @Accept json
+// @Produce json
+// @Param name query string false "Breed name prefix"
+// @Success 200 {object} BreedsResponseDoc
+// @Failure 500 {object} ErrorResponseDoc
+// @Router /breeds [get]
+func (app *application) listBreedsHandler(w http.ResponseWriter, r *http.Request) {
+	name := app.readString(r.URL.Query(), "name", "")
+
+	breeds, err := app.breedsService.GetAll(r.Context(), name)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+		return
+	}
+
+	err = app.writeJson(w, http.StatusOK, envelope{"breeds": breeds})
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+	}
+}
+
+// @Summary Get breed by ID
+// @Description Get a breed by its TheCatAPI ID
+// @Tags breeds
+// @Accept json
+// @Produce json
+// @Param id path string true "Breed ID"
+// @Success 200 {object} BreedResponseDoc
+// @Failure 404 {object} ErrorResponseDoc
+// @Failure 500 {object} ErrorResponseDoc
+// @Router /breeds/{id} [get]
+func (app *application) getBreedHandler(w http.ResponseWriter, r *http.Request) {
+	id := httprouter.ParamsFromContext(r.Context()).ByName("id")
+
+	breed, err := app.breedsService.GetById(r.Context(), id)
+	if err != nil {
+		switch {
+		case errors.Is(err, storage.ErrorModelNotFound):
+			app.notFoundResponse(w, r)
+		default:
+			app.serverErrorResponse(w, r, err)
+		}
+		return
+	}
+
+	err = app.writeJson(w, http.StatusOK, envelope{"breed": breed})
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+	}
+}
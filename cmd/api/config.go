@@ -0,0 +1,231 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log/slog"
+	"net/url"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/m1crogravity/spy-cat-agency/internal/requestid"
+	"gopkg.in/yaml.v2"
+)
+
+// envPrefix is prepended to the upper-cased flag name, with dashes replaced by
+// underscores, to get the environment variable of a setting. -db-dsn is read
+// from SCA_DB_DSN, or from the file named by SCA_DB_DSN_FILE.
+const envPrefix = "SCA_"
+
+const (
+	sourceDefault = "default"
+	sourceFile    = "file"
+	sourceEnv     = "env"
+	sourceFlag    = "flag"
+)
+
+// secretFlags are masked when the effective config is logged.
+var secretFlags = []string{"db-dsn", "bootstrap-admin-password"}
+
+// stringList is a comma separated flag value.
+type stringList []string
+
+func (l *stringList) String() string {
+	return strings.Join(*l, ",")
+}
+
+func (l *stringList) Set(val string) error {
+	*l = strings.Split(val, ",")
+	return nil
+}
+
+func envName(flagName string) string {
+	return envPrefix + strings.ToUpper(strings.ReplaceAll(flagName, "-", "_"))
+}
+
+// lookupEnv returns the value of the environment variable of a setting. A
+// value in the variable itself wins over the contents of the *_FILE one.
+func lookupEnv(flagName string) (string, bool, error) {
+	name := envName(flagName)
+	if val, ok := os.LookupEnv(name); ok {
+		return val, true, nil
+	}
+
+	path, ok := os.LookupEnv(name + "_FILE")
+	if !ok {
+		return "", false, nil
+	}
+
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return "", false, fmt.Errorf("read %s_FILE: %w", name, err)
+	}
+
+	return strings.TrimSpace(string(b)), true, nil
+}
+
+// readConfigFile reads a YAML file whose keys are flag names. Nested maps are
+// joined with dashes, so "db: {dsn: ...}" sets -db-dsn, and lists are joined
+// with commas.
+func readConfigFile(path string) (map[string]string, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var raw map[string]any
+	err = yaml.Unmarshal(b, &raw)
+	if err != nil {
+		return nil, fmt.Errorf("parse config file %s: %w", path, err)
+	}
+
+	values := make(map[string]string)
+	flattenConfig("", raw, values)
+
+	return values, nil
+}
+
+func flattenConfig(prefix string, val any, values map[string]string) {
+	switch v := val.(type) {
+	case map[string]any:
+		for key, child := range v {
+			flattenConfig(joinConfigKey(prefix, key), child, values)
+		}
+	case map[any]any:
+		for key, child := range v {
+			flattenConfig(joinConfigKey(prefix, fmt.Sprint(key)), child, values)
+		}
+	case []any:
+		items := make([]string, len(v))
+		for i, item := range v {
+			items[i] = fmt.Sprint(item)
+		}
+		values[prefix] = strings.Join(items, ",")
+	case nil:
+		values[prefix] = ""
+	default:
+		values[prefix] = fmt.Sprint(v)
+	}
+}
+
+func joinConfigKey(prefix, key string) string {
+	if prefix == "" {
+		return key
+	}
+	return prefix + "-" + key
+}
+
+// loadConfig sets every flag which wasn't given on the command line from the
+// environment, and failing that from the config file, so the precedence is
+// flag > env > file > default. It returns where every flag's value came from.
+func loadConfig(fs *flag.FlagSet, configFile string) (map[string]string, error) {
+	sources := make(map[string]string)
+	fs.VisitAll(func(f *flag.Flag) {
+		sources[f.Name] = sourceDefault
+	})
+	fs.Visit(func(f *flag.Flag) {
+		sources[f.Name] = sourceFlag
+	})
+
+	if configFile == "" {
+		if path, ok, err := lookupEnv("config"); err != nil {
+			return nil, err
+		} else if ok {
+			configFile = path
+			sources["config"] = sourceEnv
+		}
+	}
+
+	fileValues := map[string]string{}
+	if configFile != "" {
+		var err error
+		fileValues, err = readConfigFile(configFile)
+		if err != nil {
+			return nil, err
+		}
+
+		for name := range fileValues {
+			if fs.Lookup(name) == nil || name == "config" {
+				return nil, fmt.Errorf("config file %s: unknown setting %q", configFile, name)
+			}
+		}
+	}
+
+	var err error
+	fs.VisitAll(func(f *flag.Flag) {
+		if err != nil || sources[f.Name] != sourceDefault {
+			return
+		}
+
+		val, ok, lookupErr := lookupEnv(f.Name)
+		if lookupErr != nil {
+			err = lookupErr
+			return
+		}
+		source := sourceEnv
+		if !ok {
+			val, ok = fileValues[f.Name]
+			source = sourceFile
+		}
+		if !ok {
+			return
+		}
+
+		if setErr := fs.Set(f.Name, val); setErr != nil {
+			err = fmt.Errorf("invalid value %q for %s from %s: %w", val, f.Name, source, setErr)
+			return
+		}
+		sources[f.Name] = source
+	})
+
+	return sources, err
+}
+
+func maskSecret(name, value string) string {
+	if value == "" || !slices.Contains(secretFlags, name) {
+		return value
+	}
+
+	if u, err := url.Parse(value); err == nil && u.User != nil {
+		return u.Redacted()
+	}
+
+	return redactedValue
+}
+
+// logConfig logs the effective value and source of every setting with
+// secrets masked.
+func logConfig(logger *slog.Logger, fs *flag.FlagSet, sources map[string]string) {
+	attrs := make([]any, 0, len(sources))
+	fs.VisitAll(func(f *flag.Flag) {
+		attrs = append(attrs, slog.Group(f.Name,
+			"value", maskSecret(f.Name, f.Value.String()),
+			"source", sources[f.Name],
+		))
+	})
+
+	logger.Info("effective config", attrs...)
+}
+
+func newLogger(level, format string) (*slog.Logger, error) {
+	var lvl slog.Level
+	err := lvl.UnmarshalText([]byte(level))
+	if err != nil {
+		return nil, fmt.Errorf("unknown log level %q", level)
+	}
+
+	opts := &slog.HandlerOptions{Level: lvl}
+
+	var handler slog.Handler
+	switch format {
+	case "text":
+		handler = slog.NewTextHandler(os.Stdout, opts)
+	case "json":
+		handler = slog.NewJSONHandler(os.Stdout, opts)
+	default:
+		return nil, fmt.Errorf("unknown log format %q", format)
+	}
+
+	return slog.New(requestid.NewLogHandler(handler)), nil
+}
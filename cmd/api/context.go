@@ -12,6 +12,7 @@ type contextKey string
 const (
 	spyCatContextKey = contextKey("spy-cat")
 	agentContextKey  = contextKey("agent")
+	tokenContextKey  = contextKey("token")
 )
 
 func (app *application) contextSetSpyCat(r *http.Request, spyCat *model.SpyCat) *http.Request {
@@ -39,3 +40,16 @@ func (app *application) contextGetAgent(r *http.Request) *model.Agent {
 	}
 	return agent
 }
+
+func (app *application) contextSetToken(r *http.Request, token *model.Token) *http.Request {
+	ctx := context.WithValue(r.Context(), tokenContextKey, token)
+	return r.WithContext(ctx)
+}
+
+func (app *application) contextGetToken(r *http.Request) *model.Token {
+	token, ok := r.Context().Value(tokenContextKey).(*model.Token)
+	if !ok {
+		panic("missing token value in request context")
+	}
+	return token
+}
@@ -0,0 +1,80 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// @Summary Liveness check
+// @Description Report that the process is up, with its version and environment
+// @Tags health
+// @Produce json
+// @Success 200 {object} HealthcheckResponseDoc
+// @Failure 500 {object} ErrorResponseDoc
+// @Router /healthcheck [get]
+func (app *application) healthcheckHandler(w http.ResponseWriter, r *http.Request) {
+	env := envelope{
+		"status": "available",
+		"system_info": map[string]string{
+			"environment": app.config.env,
+			"version":     version,
+		},
+	}
+
+	err := app.writeJson(w, http.StatusOK, env)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+	}
+}
+
+// @Summary Readiness check
+// @Description Report whether the database and the breed list are available. Becomes not ready once shutdown starts. Failure details are logged, not returned.
+// @Tags health
+// @Produce json
+// @Success 200 {object} ReadinessResponseDoc
+// @Failure 503 {object} ReadinessResponseDoc
+// @Failure 500 {object} ErrorResponseDoc
+// @Router /readyz [get]
+func (app *application) readinessHandler(w http.ResponseWriter, r *http.Request) {
+	status := http.StatusOK
+	checks := map[string]string{}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 2*time.Second)
+	defer cancel()
+
+	select {
+	case <-app.shutdown:
+		status = http.StatusServiceUnavailable
+		checks["server"] = "shutting down"
+	default:
+		checks["server"] = "up"
+	}
+
+	if err := app.db.Ping(ctx); err != nil {
+		status = http.StatusServiceUnavailable
+		checks["database"] = "unavailable"
+		app.logError(r, fmt.Errorf("readiness check database: %w", err))
+	} else {
+		checks["database"] = "up"
+	}
+
+	if _, err := app.breeds.FindAll(ctx); err != nil {
+		status = http.StatusServiceUnavailable
+		checks["breeds"] = "unavailable"
+		app.logError(r, fmt.Errorf("readiness check breeds: %w", err))
+	} else {
+		checks["breeds"] = "up"
+	}
+
+	env := envelope{"status": "ready", "checks": checks}
+	if status != http.StatusOK {
+		env["status"] = "not ready"
+	}
+
+	err := app.writeJson(w, status, env)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+	}
+}
@@ -1,15 +1,21 @@
 package main
 
 import (
+	"bytes"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
 	"net/http"
+	"net/url"
+	"slices"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/julienschmidt/httprouter"
+	"github.com/m1crogravity/spy-cat-agency/internal/model"
+	"github.com/m1crogravity/spy-cat-agency/internal/validator"
 )
 
 type envelope map[string]any
@@ -44,7 +50,43 @@ func (app *application) readJSON(w http.ResponseWriter, r *http.Request, dst any
 	maxBytes := 1_048_576
 	r.Body = http.MaxBytesReader(w, r.Body, int64(maxBytes))
 
-	dec := json.NewDecoder(r.Body)
+	return decodeJSON(r.Body, dst)
+}
+
+// readMergePatch reads a JSON Merge Patch body into dst and also returns the
+// keys explicitly set to null, which a pointer field can't tell from absent
+// ones.
+func (app *application) readMergePatch(w http.ResponseWriter, r *http.Request, dst any) ([]string, error) {
+	var body json.RawMessage
+	err := app.readJSON(w, r, &body)
+	if err != nil {
+		return nil, err
+	}
+
+	err = decodeJSON(bytes.NewReader(body), dst)
+	if err != nil {
+		return nil, err
+	}
+
+	var fields map[string]json.RawMessage
+	err = json.Unmarshal(body, &fields)
+	if err != nil || fields == nil {
+		return nil, errors.New("body must be a JSON object")
+	}
+
+	var nulls []string
+	for key, value := range fields {
+		if string(value) == "null" {
+			nulls = append(nulls, key)
+		}
+	}
+	slices.Sort(nulls)
+
+	return nulls, nil
+}
+
+func decodeJSON(body io.Reader, dst any) error {
+	dec := json.NewDecoder(body)
 	dec.DisallowUnknownFields()
 
 	err := dec.Decode(dst)
@@ -84,3 +126,72 @@ func (app *application) readJSON(w http.ResponseWriter, r *http.Request, dst any
 		return err
 	}
 }
+
+func (app *application) readString(qs url.Values, key string, defaultValue string) string {
+	s := qs.Get(key)
+	if s == "" {
+		return defaultValue
+	}
+
+	return s
+}
+
+func (app *application) readInt(qs url.Values, key string, defaultValue int, v *validator.Validator) int {
+	s := qs.Get(key)
+	if s == "" {
+		return defaultValue
+	}
+
+	i, err := strconv.Atoi(s)
+	if err != nil {
+		v.AddError(key, "must be an integer value")
+		return defaultValue
+	}
+
+	return i
+}
+
+func (app *application) readOptionalInt(qs url.Values, key string, v *validator.Validator) *int {
+	s := qs.Get(key)
+	if s == "" {
+		return nil
+	}
+
+	i, err := strconv.Atoi(s)
+	if err != nil {
+		v.AddError(key, "must be an integer value")
+		return nil
+	}
+
+	return &i
+}
+
+func (app *application) readOptionalMoney(qs url.Values, key string, currency string, v *validator.Validator) *model.Money {
+	s := qs.Get(key)
+	if s == "" {
+		return nil
+	}
+
+	m, err := model.ParseMoney(s, currency)
+	if err != nil {
+		v.AddError(key, err.Error())
+		return nil
+	}
+
+	return &m
+}
+
+func (app *application) readMonth(qs url.Values, key string, v *validator.Validator) time.Time {
+	s := qs.Get(key)
+	if s == "" {
+		return time.Time{}
+	}
+
+	t, err := time.Parse(model.MonthLayout, s)
+	if err != nil {
+		v.AddError(key, "must be a month in YYYY-MM format")
+		return time.Time{}
+	}
+
+	return t
+}
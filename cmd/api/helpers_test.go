@@ -0,0 +1,77 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestReadMergePatch(t *testing.T) {
+	tc := []struct {
+		name  string
+		body  string
+		nulls []string
+		err   string
+	}{
+		{
+			name: "absent fields",
+			body: `{"name":"Tom"}`,
+		},
+		{
+			name:  "explicit nulls",
+			body:  `{"years_of_experience":null,"name":"Tom","breed":null}`,
+			nulls: []string{"breed", "years_of_experience"},
+		},
+		{
+			name: "unknown field",
+			body: `{"nickname":null}`,
+			err:  `body contains unknown key "nickname"`,
+		},
+		{
+			name: "incorrect type",
+			body: `{"years_of_experience":"three"}`,
+			err:  `body contains incorrect JSON type for field "years_of_experience"`,
+		},
+		{
+			name: "not an object",
+			body: `null`,
+			err:  "body must be a JSON object",
+		},
+		{
+			name: "several values",
+			body: `{} {}`,
+			err:  "body must contain a single JSON value",
+		},
+	}
+
+	app := &application{}
+	for _, tt := range tc {
+		t.Run(tt.name, func(t *testing.T) {
+			var input struct {
+				Name              *string `json:"name"`
+				YearsOfExperience *int    `json:"years_of_experience"`
+				Breed             *string `json:"breed"`
+			}
+
+			r := httptest.NewRequest(http.MethodPatch, "/v1/spy-cats/1", strings.NewReader(tt.body))
+			nulls, err := app.readMergePatch(httptest.NewRecorder(), r, &input)
+			if tt.err != "" {
+				if err == nil || err.Error() != tt.err {
+					t.Fatalf("expected error %q, got %v", tt.err, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !slices.Equal(nulls, tt.nulls) {
+				t.Fatalf("expected nulls %v, got %v", tt.nulls, nulls)
+			}
+			if input.Name == nil || *input.Name != "Tom" {
+				t.Fatal("expected the name to be read")
+			}
+		})
+	}
+}
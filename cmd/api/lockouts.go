@@ -0,0 +1,81 @@
+package main
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/m1crogravity/spy-cat-agency/internal/model"
+	"github.com/m1crogravity/spy-cat-agency/internal/storage"
+	"github.com/m1crogravity/spy-cat-agency/internal/validator"
+)
+
+// @Summary List login lockouts
+// @Description Get all names which are currently locked out after failed logins
+// @Tags lockouts
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} LockoutsResponseDoc
+// @Failure 401 {object} ErrorResponseDoc
+// @Failure 403 {object} ErrorResponseDoc
+// @Failure 500 {object} ErrorResponseDoc
+// @Router /lockouts [get]
+func (app *application) listLockoutsHandler(w http.ResponseWriter, r *http.Request) {
+	lockouts, err := app.lockoutsService.GetAllLocked(r.Context())
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+		return
+	}
+
+	err = app.writeJson(w, http.StatusOK, envelope{"lockouts": lockouts})
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+	}
+}
+
+// @Summary Clear login lockout
+// @Description Forget failed logins of a name, which immediately lifts its lockout
+// @Tags lockouts
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param user_type query string true "User type (spy-cat, agent)"
+// @Param name query string true "Login name"
+// @Success 200 {object} MessageResponseDoc
+// @Failure 401 {object} ErrorResponseDoc
+// @Failure 403 {object} ErrorResponseDoc
+// @Failure 404 {object} ErrorResponseDoc
+// @Failure 422 {object} ValidationErrorResponseDoc
+// @Failure 500 {object} ErrorResponseDoc
+// @Router /lockouts [delete]
+func (app *application) clearLockoutHandler(w http.ResponseWriter, r *http.Request) {
+	qs := r.URL.Query()
+
+	userType := model.UserType(app.readString(qs, "user_type", ""))
+	name := app.readString(qs, "name", "")
+
+	v := validator.New()
+	model.ValidateUserType(v, userType)
+	v.Check(name != "", "name", "must be provided")
+
+	if !v.Valid() {
+		app.failedValidationResponse(w, r, v.Errors)
+		return
+	}
+
+	err := app.lockoutsService.Clear(r.Context(), userType, name)
+	if err != nil {
+		switch {
+		case errors.Is(err, storage.ErrorModelNotFound):
+			app.notFoundResponse(w, r)
+		default:
+			app.serverErrorResponse(w, r, err)
+		}
+		return
+	}
+
+	err = app.writeJson(w, http.StatusOK, envelope{"message": "lockout successfully cleared"})
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+	}
+}
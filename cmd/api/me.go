@@ -0,0 +1,110 @@
+package main
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/m1crogravity/spy-cat-agency/internal/model"
+	"github.com/m1crogravity/spy-cat-agency/internal/storage"
+	"github.com/m1crogravity/spy-cat-agency/internal/validator"
+)
+
+// @Summary Get current spy cat
+// @Description Get the profile of the authenticated spy cat
+// @Tags me
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} SpyCatResponseDoc
+// @Failure 401 {object} ErrorResponseDoc
+// @Failure 500 {object} ErrorResponseDoc
+// @Router /me [get]
+func (app *application) showMeHandler(w http.ResponseWriter, r *http.Request) {
+	spyCat := app.contextGetSpyCat(r)
+
+	err := app.writeJson(w, http.StatusOK, envelope{"spy-cat": spyCat})
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+	}
+}
+
+// @Summary Get current mission
+// @Description Get the mission the authenticated spy cat is currently working on
+// @Tags me
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} MissionResponseDoc
+// @Failure 401 {object} ErrorResponseDoc
+// @Failure 404 {object} ErrorResponseDoc
+// @Failure 500 {object} ErrorResponseDoc
+// @Router /me/mission [get]
+func (app *application) showMyActiveMissionHandler(w http.ResponseWriter, r *http.Request) {
+	spyCat := app.contextGetSpyCat(r)
+
+	mission, err := app.missionsService.GetActiveMission(r.Context(), spyCat)
+	if err != nil {
+		switch {
+		case errors.Is(err, storage.ErrorModelNotFound):
+			app.notFoundResponse(w, r)
+		default:
+			app.serverErrorResponse(w, r, err)
+		}
+		return
+	}
+
+	err = app.writeJson(w, http.StatusOK, envelope{"mission": mission})
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+	}
+}
+
+// @Summary List own missions
+// @Description Get the mission history of the authenticated spy cat ordered by ID. Use the returned next_cursor to fetch the following page.
+// @Tags me
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param state query string false "Mission state" Enums(created, in_progress, completed, aborted, failed)
+// @Param cursor query string false "Cursor returned by the previous page"
+// @Param limit query int false "Page size" default(20)
+// @Success 200 {object} MissionsResponseDoc
+// @Failure 401 {object} ErrorResponseDoc
+// @Failure 422 {object} ValidationErrorResponseDoc
+// @Failure 500 {object} ErrorResponseDoc
+// @Router /me/missions [get]
+func (app *application) listMyMissionsHandler(w http.ResponseWriter, r *http.Request) {
+	spyCat := app.contextGetSpyCat(r)
+
+	filter := model.MissionsFilter{
+		SpyCatId: spyCat.Id,
+	}
+
+	v := validator.New()
+	qs := r.URL.Query()
+
+	filter.State = model.CompleteState(app.readString(qs, "state", ""))
+	filter.Limit = app.readInt(qs, "limit", 20, v)
+
+	after, err := model.DecodeCursor(app.readString(qs, "cursor", ""))
+	if err != nil {
+		v.AddError("cursor", err.Error())
+	}
+	filter.After = after
+
+	if model.ValidateMissionsFilter(v, filter); !v.Valid() {
+		app.failedValidationResponse(w, r, v.Errors)
+		return
+	}
+
+	missions, metadata, err := app.missionsService.GetAll(r.Context(), filter)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+		return
+	}
+
+	err = app.writeJson(w, http.StatusOK, envelope{"missions": missions, "metadata": metadata})
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+	}
+}
@@ -0,0 +1,190 @@
+package main
+
+import (
+	"bufio"
+	"cmp"
+	"fmt"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/m1crogravity/spy-cat-agency/internal/model"
+)
+
+var latencyBuckets = []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10}
+
+type requestLabels struct {
+	method string
+	route  string
+	status int
+}
+
+type histogram struct {
+	counts []uint64
+	sum    float64
+	count  uint64
+}
+
+func (h *histogram) observe(v float64) {
+	for i, upper := range latencyBuckets {
+		if v <= upper {
+			h.counts[i]++
+		}
+	}
+	h.sum += v
+	h.count++
+}
+
+// metrics collects HTTP request metrics. Everything else is read at scrape time.
+type metrics struct {
+	mu       sync.Mutex
+	requests map[requestLabels]*histogram
+}
+
+func newMetrics() *metrics {
+	return &metrics{
+		requests: make(map[requestLabels]*histogram),
+	}
+}
+
+func (m *metrics) observeRequest(method, route string, status int, took time.Duration) {
+	if route == "" {
+		route = "unmatched"
+	}
+	labels := requestLabels{method: method, route: route, status: status}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	h, ok := m.requests[labels]
+	if !ok {
+		h = &histogram{counts: make([]uint64, len(latencyBuckets))}
+		m.requests[labels] = h
+	}
+	h.observe(took.Seconds())
+}
+
+func (m *metrics) snapshot() ([]requestLabels, map[requestLabels]histogram) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	keys := make([]requestLabels, 0, len(m.requests))
+	values := make(map[requestLabels]histogram, len(m.requests))
+	for k, h := range m.requests {
+		keys = append(keys, k)
+		values[k] = histogram{counts: slices.Clone(h.counts), sum: h.sum, count: h.count}
+	}
+
+	slices.SortFunc(keys, func(a, b requestLabels) int {
+		return cmp.Or(
+			strings.Compare(a.route, b.route),
+			strings.Compare(a.method, b.method),
+			cmp.Compare(a.status, b.status),
+		)
+	})
+
+	return keys, values
+}
+
+var labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+// metricsWriter writes metrics in the Prometheus text exposition format.
+type metricsWriter struct {
+	*bufio.Writer
+}
+
+func (mw metricsWriter) header(name, kind, help string) {
+	fmt.Fprintf(mw, "# HELP %s %s\n", name, help)
+	fmt.Fprintf(mw, "# TYPE %s %s\n", name, kind)
+}
+
+func (mw metricsWriter) sample(name string, value float64, labels ...string) {
+	mw.WriteString(name)
+	if len(labels) > 0 {
+		mw.WriteByte('{')
+		for i := 0; i+1 < len(labels); i += 2 {
+			if i > 0 {
+				mw.WriteByte(',')
+			}
+			fmt.Fprintf(mw, `%s="%s"`, labels[i], labelEscaper.Replace(labels[i+1]))
+		}
+		mw.WriteByte('}')
+	}
+	mw.WriteByte(' ')
+	mw.WriteString(strconv.FormatFloat(value, 'g', -1, 64))
+	mw.WriteByte('\n')
+}
+
+func (app *application) metricsHandler(w http.ResponseWriter, r *http.Request) {
+	missions, err := app.missionsService.CountByState(r.Context())
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+
+	mw := metricsWriter{bufio.NewWriter(w)}
+	defer mw.Flush()
+
+	keys, requests := app.metrics.snapshot()
+
+	mw.header("http_requests_total", "counter", "Total number of HTTP requests.")
+	for _, k := range keys {
+		mw.sample("http_requests_total", float64(requests[k].count),
+			"method", k.method, "route", k.route, "status", strconv.Itoa(k.status))
+	}
+
+	mw.header("http_request_duration_seconds", "histogram", "HTTP request latency.")
+	for _, k := range keys {
+		h := requests[k]
+		labels := []string{"method", k.method, "route", k.route, "status", strconv.Itoa(k.status)}
+		for i, upper := range latencyBuckets {
+			mw.sample("http_request_duration_seconds_bucket", float64(h.counts[i]),
+				append(labels, "le", strconv.FormatFloat(upper, 'g', -1, 64))...)
+		}
+		mw.sample("http_request_duration_seconds_bucket", float64(h.count), append(labels, "le", "+Inf")...)
+		mw.sample("http_request_duration_seconds_sum", h.sum, labels...)
+		mw.sample("http_request_duration_seconds_count", float64(h.count), labels...)
+	}
+
+	if app.db != nil {
+		stat := app.db.Stat()
+		mw.header("db_pool_acquired_conns", "gauge", "Number of currently acquired connections.")
+		mw.sample("db_pool_acquired_conns", float64(stat.AcquiredConns()))
+		mw.header("db_pool_idle_conns", "gauge", "Number of currently idle connections.")
+		mw.sample("db_pool_idle_conns", float64(stat.IdleConns()))
+		mw.header("db_pool_total_conns", "gauge", "Total number of connections in the pool.")
+		mw.sample("db_pool_total_conns", float64(stat.TotalConns()))
+		mw.header("db_pool_max_conns", "gauge", "Maximum size of the pool.")
+		mw.sample("db_pool_max_conns", float64(stat.MaxConns()))
+		mw.header("db_pool_acquire_count_total", "counter", "Number of successful connection acquires.")
+		mw.sample("db_pool_acquire_count_total", float64(stat.AcquireCount()))
+		mw.header("db_pool_empty_acquire_count_total", "counter", "Number of acquires which had to wait for a connection.")
+		mw.sample("db_pool_empty_acquire_count_total", float64(stat.EmptyAcquireCount()))
+		mw.header("db_pool_acquire_wait_seconds_total", "counter", "Total time spent waiting for a connection.")
+		mw.sample("db_pool_acquire_wait_seconds_total", stat.AcquireDuration().Seconds())
+	}
+
+	if app.breeds != nil {
+		stats := app.breeds.Stats()
+		mw.header("breeds_cache_lookups_total", "counter", "Breed cache lookups by result.")
+		mw.sample("breeds_cache_lookups_total", float64(stats.CacheHits), "result", "hit")
+		mw.sample("breeds_cache_lookups_total", float64(stats.CacheStaleHits), "result", "stale")
+		mw.sample("breeds_cache_lookups_total", float64(stats.CacheMisses), "result", "miss")
+		mw.header("breeds_api_requests_total", "counter", "Calls to TheCatAPI by outcome.")
+		mw.sample("breeds_api_requests_total", float64(stats.RequestsSucceeded), "outcome", "success")
+		mw.sample("breeds_api_requests_total", float64(stats.UnexpectedStatus), "outcome", "unexpected_status")
+		mw.sample("breeds_api_requests_total", float64(stats.RequestsFailed), "outcome", "error")
+		mw.header("breeds_store_fallbacks_total", "counter", "Times the persisted breed list was served because TheCatAPI was unavailable.")
+		mw.sample("breeds_store_fallbacks_total", float64(stats.StoreFallbacks))
+	}
+
+	mw.header("missions", "gauge", "Number of missions by state.")
+	for _, state := range model.CompleteStates {
+		mw.sample("missions", float64(missions[state]), "state", string(state))
+	}
+}
@@ -6,14 +6,30 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"slices"
 	"strings"
 	"time"
 
 	"github.com/m1crogravity/spy-cat-agency/internal/model"
+	"github.com/m1crogravity/spy-cat-agency/internal/requestid"
 	"github.com/m1crogravity/spy-cat-agency/internal/storage"
 	"github.com/m1crogravity/spy-cat-agency/internal/validator"
 )
 
+func (app *application) requestID(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := r.Header.Get(requestid.Header)
+		if !requestid.Valid(id) {
+			id = requestid.Generate()
+		}
+
+		w.Header().Set(requestid.Header, id)
+		r = r.WithContext(requestid.NewContext(r.Context(), id))
+
+		next.ServeHTTP(w, r)
+	})
+}
+
 func (app *application) recoverPanic(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		defer func() {
@@ -29,37 +45,83 @@ func (app *application) recoverPanic(next http.Handler) http.Handler {
 
 func (app *application) logRequestResponse(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requestPolicy, responsePolicy := app.redactor.policies(r)
 		rw := &responseWriter{
 			ResponseWriter: w,
 			statusCode:     http.StatusOK,
+			bodyLimit:      app.redactor.bodyLimit(responsePolicy),
 		}
-		reqUrl := r.URL.String()
-		if strings.Contains(reqUrl, "swagger") {
+		received := time.Now()
+		if requestPolicy == bodyLogOff && responsePolicy == bodyLogOff {
 			next.ServeHTTP(rw, r)
+			app.metrics.observeRequest(r.Method, rw.route, rw.statusCode, time.Since(received))
 			return
 		}
-		received := time.Now()
-		bodyBytes, _ := io.ReadAll(r.Body)
-		r.Body = io.NopCloser(bytes.NewBuffer(bodyBytes))
 
-		app.logger.Info("<-request:",
+		var bodyBytes []byte
+		bodySize := 0
+		if limit := app.redactor.bodyLimit(requestPolicy); limit != 0 {
+			reader := io.Reader(r.Body)
+			if limit > 0 {
+				reader = io.LimitReader(r.Body, int64(limit))
+			}
+			bodyBytes, _ = io.ReadAll(reader)
+			bodySize = len(bodyBytes)
+			if limit > 0 && r.ContentLength > int64(bodySize) {
+				bodySize = int(r.ContentLength)
+			}
+			r.Body = struct {
+				io.Reader
+				io.Closer
+			}{io.MultiReader(bytes.NewReader(bodyBytes), r.Body), r.Body}
+		}
+
+		app.logger.InfoContext(r.Context(), "<-request:",
 			"method", r.Method,
-			"url", reqUrl,
+			"url", r.URL.String(),
 			"ip", r.RemoteAddr,
-			"body", string(bodyBytes),
+			"headers", app.redactor.header(r.Header),
+			"body", app.redactor.body(bodyBytes, bodySize),
 			"received_at", received.Format(time.RFC3339),
 		)
 
 		next.ServeHTTP(rw, r)
+		took := time.Since(received)
+		app.metrics.observeRequest(r.Method, rw.route, rw.statusCode, took)
 
-		app.logger.Info("->response:",
+		app.logger.InfoContext(r.Context(), "->response:",
 			"code", rw.statusCode,
-			"body", rw.body.String(),
-			"took", time.Since(received),
+			"body", app.redactor.body(rw.body.Bytes(), rw.bodySize),
+			"took", took,
 		)
 	})
 }
 
+func (app *application) enableCORS(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Add("Vary", "Origin")
+		w.Header().Add("Vary", "Access-Control-Request-Method")
+		w.Header().Add("Vary", "Access-Control-Request-Headers")
+
+		origin := r.Header.Get("Origin")
+		if origin != "" && slices.Contains(app.config.cors.trustedOrigins, origin) {
+			w.Header().Set("Access-Control-Allow-Origin", origin)
+			w.Header().Set("Access-Control-Expose-Headers", "Retry-After, X-Request-ID")
+
+			if r.Method == http.MethodOptions && r.Header.Get("Access-Control-Request-Method") != "" {
+				w.Header().Set("Access-Control-Allow-Methods", "OPTIONS, GET, POST, PUT, PATCH, DELETE")
+				w.Header().Set("Access-Control-Allow-Headers", "Authorization, Content-Type, X-Request-ID")
+				w.Header().Set("Access-Control-Max-Age", "600")
+
+				w.WriteHeader(http.StatusOK)
+				return
+			}
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
 func (app *application) authenticate(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		w.Header().Add("Vary", "Authorization")
@@ -98,6 +160,8 @@ func (app *application) authenticate(next http.Handler) http.Handler {
 			return
 		}
 
+		r = app.contextSetToken(r, token)
+
 		switch token.UserType {
 		case model.SpyCatUserType:
 			spyCat, err := app.spyCatsService.GetById(r.Context(), token.UserID)
@@ -156,3 +220,28 @@ func (app *application) requireAgent(next http.HandlerFunc) http.HandlerFunc {
 		next.ServeHTTP(w, r)
 	})
 }
+
+func (app *application) requireAuthenticatedUser(next http.HandlerFunc) http.HandlerFunc {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if app.contextGetSpyCat(r).IsAnonymous() && app.contextGetAgent(r).IsAnonymous() {
+			app.authenticationRequiredResponse(w, r)
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+func (app *application) requirePermission(permission model.Permission, next http.HandlerFunc) http.HandlerFunc {
+	fn := func(w http.ResponseWriter, r *http.Request) {
+		agent := app.contextGetAgent(r)
+		if !agent.HasPermission(permission) {
+			app.notPermittedResponse(w, r)
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	}
+
+	return app.requireAgent(fn)
+}
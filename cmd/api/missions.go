@@ -7,6 +7,7 @@ import (
 	"github.com/m1crogravity/spy-cat-agency/internal/model"
 	"github.com/m1crogravity/spy-cat-agency/internal/service"
 	"github.com/m1crogravity/spy-cat-agency/internal/storage"
+	"github.com/m1crogravity/spy-cat-agency/internal/validator"
 )
 
 type inputTarget struct {
@@ -24,6 +25,7 @@ type inputTarget struct {
 // @Success 201 {object} MissionResponseDoc
 // @Failure 400 {object} ErrorResponseDoc
 // @Failure 401 {object} ErrorResponseDoc
+// @Failure 403 {object} ErrorResponseDoc
 // @Failure 422 {object} ValidationErrorResponseDoc
 // @Failure 500 {object} ErrorResponseDoc
 // @Router /missions [post]
@@ -73,23 +75,51 @@ func (app *application) createMissionHandler(w http.ResponseWriter, r *http.Requ
 }
 
 // @Summary List all missions
-// @Description Get a list of all missions
+// @Description Get a list of missions ordered by ID, optionally filtered. Use the returned next_cursor to fetch the following page.
 // @Tags missions
 // @Accept json
 // @Produce json
 // @Security BearerAuth
+// @Param state query string false "Mission state" Enums(created, in_progress, completed, aborted, failed)
+// @Param spy_cat_id query int false "Assigned spy cat ID"
+// @Param country query string false "Country of at least one of the mission targets"
+// @Param cursor query string false "Cursor returned by the previous page"
+// @Param limit query int false "Page size" default(20)
 // @Success 200 {object} MissionsResponseDoc
 // @Failure 401 {object} ErrorResponseDoc
+// @Failure 403 {object} ErrorResponseDoc
+// @Failure 422 {object} ValidationErrorResponseDoc
 // @Failure 500 {object} ErrorResponseDoc
 // @Router /missions [get]
 func (app *application) listMissionHandler(w http.ResponseWriter, r *http.Request) {
-	missions, err := app.missionsService.GetAll(r.Context())
+	var filter model.MissionsFilter
+
+	v := validator.New()
+	qs := r.URL.Query()
+
+	filter.State = model.CompleteState(app.readString(qs, "state", ""))
+	filter.SpyCatId = int64(app.readInt(qs, "spy_cat_id", 0, v))
+	filter.Country = app.readString(qs, "country", "")
+	filter.Limit = app.readInt(qs, "limit", 20, v)
+
+	after, err := model.DecodeCursor(app.readString(qs, "cursor", ""))
+	if err != nil {
+		v.AddError("cursor", err.Error())
+	}
+	filter.After = after
+
+	if model.ValidateMissionsFilter(v, filter); !v.Valid() {
+		app.failedValidationResponse(w, r, v.Errors)
+		return
+	}
+
+	missions, metadata, err := app.missionsService.GetAll(r.Context(), filter)
 	if err != nil {
 		app.serverErrorResponse(w, r, err)
 		return
 	}
 
-	err = app.writeJson(w, http.StatusOK, envelope{"missions": missions})
+	err = app.writeJson(w, http.StatusOK, envelope{"missions": missions, "metadata": metadata})
 	if err != nil {
 		app.serverErrorResponse(w, r, err)
 	}
@@ -104,6 +134,7 @@ func (app *application) listMissionHandler(w http.ResponseWriter, r *http.Reques
 // @Param id path int true "Mission ID"
 // @Success 200 {object} MissionResponseDoc
 // @Failure 401 {object} ErrorResponseDoc
+// @Failure 403 {object} ErrorResponseDoc
 // @Failure 404 {object} ErrorResponseDoc
 // @Failure 500 {object} ErrorResponseDoc
 // @Router /missions/{id} [get]
@@ -140,6 +171,7 @@ func (app *application) getMissionHandler(w http.ResponseWriter, r *http.Request
 // @Param id path int true "Mission ID"
 // @Success 200 {object} MessageResponseDoc
 // @Failure 401 {object} ErrorResponseDoc
+// @Failure 403 {object} ErrorResponseDoc
 // @Failure 404 {object} ErrorResponseDoc
 // @Failure 500 {object} ErrorResponseDoc
 // @Router /missions/{id} [delete]
@@ -168,7 +200,7 @@ func (app *application) deleteMissionHandler(w http.ResponseWriter, r *http.Requ
 }
 
 // @Summary Complete a mission
-// @Description Mark a mission as completed
+// @Description Mark a mission in progress as completed
 // @Tags missions
 // @Accept json
 // @Produce json
@@ -176,7 +208,9 @@ func (app *application) deleteMissionHandler(w http.ResponseWriter, r *http.Requ
 // @Param id path int true "Mission ID"
 // @Success 200 {object} MissionResponseDoc
 // @Failure 401 {object} ErrorResponseDoc
+// @Failure 403 {object} ErrorResponseDoc
 // @Failure 404 {object} ErrorResponseDoc
+// @Failure 409 {object} ErrorResponseDoc
 // @Failure 500 {object} ErrorResponseDoc
 // @Router /missions/{id}/complete [patch]
 func (app *application) completeMissionHandler(w http.ResponseWriter, r *http.Request) {
@@ -191,6 +225,10 @@ func (app *application) completeMissionHandler(w http.ResponseWriter, r *http.Re
 		switch {
 		case errors.Is(err, storage.ErrorModelNotFound):
 			app.notFoundResponse(w, r)
+		case errors.Is(err, model.ErrInvalidTransition):
+			app.conflictResponse(w, r, err)
+		case errors.Is(err, storage.ErrorEditConflict):
+			app.editConflictResponse(w, r)
 		default:
 			app.serverErrorResponse(w, r, err)
 		}
@@ -204,6 +242,170 @@ func (app *application) completeMissionHandler(w http.ResponseWriter, r *http.Re
 	}
 }
 
+// @Summary Abort a mission
+// @Description Call off a created or in progress mission, the reason is required
+// @Tags missions
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Mission ID"
+// @Param reason body MissionReasonRequestDoc true "Abort Reason"
+// @Success 200 {object} MissionResponseDoc
+// @Failure 400 {object} ErrorResponseDoc
+// @Failure 401 {object} ErrorResponseDoc
+// @Failure 403 {object} ErrorResponseDoc
+// @Failure 404 {object} ErrorResponseDoc
+// @Failure 409 {object} ErrorResponseDoc
+// @Failure 422 {object} ValidationErrorResponseDoc
+// @Failure 500 {object} ErrorResponseDoc
+// @Router /missions/{id}/abort [patch]
+func (app *application) abortMissionHandler(w http.ResponseWriter, r *http.Request) {
+	id, err := app.readIDParam(r, "id")
+	if err != nil {
+		app.notFoundResponse(w, r)
+		return
+	}
+
+	var input struct {
+		Reason string `json:"reason"`
+	}
+
+	err = app.readJSON(w, r, &input)
+	if err != nil {
+		app.badRequestResponse(w, r, err)
+		return
+	}
+
+	v := validator.New()
+	if model.ValidateMissionReason(v, input.Reason); !v.Valid() {
+		app.failedValidationResponse(w, r, v.Errors)
+		return
+	}
+
+	mission, err := app.missionsService.AbortMission(r.Context(), id, input.Reason)
+	if err != nil {
+		switch {
+		case errors.Is(err, storage.ErrorModelNotFound):
+			app.notFoundResponse(w, r)
+		case errors.Is(err, model.ErrInvalidTransition):
+			app.conflictResponse(w, r, err)
+		case errors.Is(err, storage.ErrorEditConflict):
+			app.editConflictResponse(w, r)
+		default:
+			app.serverErrorResponse(w, r, err)
+		}
+		return
+	}
+
+	err = app.writeJson(w, http.StatusOK, envelope{"mission": mission})
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+	}
+}
+
+// @Summary Fail a mission
+// @Description Mark a mission in progress as failed, the reason is required
+// @Tags missions
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Mission ID"
+// @Param reason body MissionReasonRequestDoc true "Failure Reason"
+// @Success 200 {object} MissionResponseDoc
+// @Failure 400 {object} ErrorResponseDoc
+// @Failure 401 {object} ErrorResponseDoc
+// @Failure 403 {object} ErrorResponseDoc
+// @Failure 404 {object} ErrorResponseDoc
+// @Failure 409 {object} ErrorResponseDoc
+// @Failure 422 {object} ValidationErrorResponseDoc
+// @Failure 500 {object} ErrorResponseDoc
+// @Router /missions/{id}/fail [patch]
+func (app *application) failMissionHandler(w http.ResponseWriter, r *http.Request) {
+	id, err := app.readIDParam(r, "id")
+	if err != nil {
+		app.notFoundResponse(w, r)
+		return
+	}
+
+	var input struct {
+		Reason string `json:"reason"`
+	}
+
+	err = app.readJSON(w, r, &input)
+	if err != nil {
+		app.badRequestResponse(w, r, err)
+		return
+	}
+
+	v := validator.New()
+	if model.ValidateMissionReason(v, input.Reason); !v.Valid() {
+		app.failedValidationResponse(w, r, v.Errors)
+		return
+	}
+
+	mission, err := app.missionsService.FailMission(r.Context(), id, input.Reason)
+	if err != nil {
+		switch {
+		case errors.Is(err, storage.ErrorModelNotFound):
+			app.notFoundResponse(w, r)
+		case errors.Is(err, model.ErrInvalidTransition):
+			app.conflictResponse(w, r, err)
+		case errors.Is(err, storage.ErrorEditConflict):
+			app.editConflictResponse(w, r)
+		default:
+			app.serverErrorResponse(w, r, err)
+		}
+		return
+	}
+
+	err = app.writeJson(w, http.StatusOK, envelope{"mission": mission})
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+	}
+}
+
+// @Summary Reopen a mission
+// @Description Bring a completed, aborted or failed mission back to created. The spy cat is released and unfinished targets return to created. If every target is completed, they all return to created.
+// @Tags missions
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Mission ID"
+// @Success 200 {object} MissionResponseDoc
+// @Failure 401 {object} ErrorResponseDoc
+// @Failure 403 {object} ErrorResponseDoc
+// @Failure 404 {object} ErrorResponseDoc
+// @Failure 409 {object} ErrorResponseDoc
+// @Failure 500 {object} ErrorResponseDoc
+// @Router /missions/{id}/reopen [patch]
+func (app *application) reopenMissionHandler(w http.ResponseWriter, r *http.Request) {
+	id, err := app.readIDParam(r, "id")
+	if err != nil {
+		app.notFoundResponse(w, r)
+		return
+	}
+
+	mission, err := app.missionsService.ReopenMission(r.Context(), id)
+	if err != nil {
+		switch {
+		case errors.Is(err, storage.ErrorModelNotFound):
+			app.notFoundResponse(w, r)
+		case errors.Is(err, model.ErrInvalidTransition):
+			app.conflictResponse(w, r, err)
+		case errors.Is(err, storage.ErrorEditConflict):
+			app.editConflictResponse(w, r)
+		default:
+			app.serverErrorResponse(w, r, err)
+		}
+		return
+	}
+
+	err = app.writeJson(w, http.StatusOK, envelope{"mission": mission})
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+	}
+}
+
 // @Summary Assign mission to spy cat
 // @Description Assign a mission to a specific spy cat
 // @Tags missions
@@ -214,7 +416,9 @@ func (app *application) completeMissionHandler(w http.ResponseWriter, r *http.Re
 // @Param spy-cat-id path int true "Spy Cat ID"
 // @Success 200 {object} MissionResponseDoc
 // @Failure 401 {object} ErrorResponseDoc
+// @Failure 403 {object} ErrorResponseDoc
 // @Failure 404 {object} ErrorResponseDoc
+// @Failure 409 {object} ErrorResponseDoc
 // @Failure 500 {object} ErrorResponseDoc
 // @Router /missions/{id}/spy-cat/{spy-cat-id} [patch]
 func (app *application) assignMissionHandler(w http.ResponseWriter, r *http.Request) {
@@ -253,8 +457,14 @@ func (app *application) assignMissionHandler(w http.ResponseWriter, r *http.Requ
 	err = app.missionsService.AssignMission(r.Context(), mission, spyCat)
 	if err != nil {
 		switch {
+		case errors.Is(err, storage.ErrorModelNotFound):
+			app.notFoundResponse(w, r)
 		case errors.Is(err, service.ErrAlreadyAssigned) || errors.Is(err, service.ErrSpyCatIsBusy):
 			app.badRequestResponse(w, r, err)
+		case errors.Is(err, model.ErrInvalidTransition):
+			app.conflictResponse(w, r, err)
+		case errors.Is(err, storage.ErrorEditConflict):
+			app.editConflictResponse(w, r)
 		default:
 			app.serverErrorResponse(w, r, err)
 		}
@@ -267,6 +477,178 @@ func (app *application) assignMissionHandler(w http.ResponseWriter, r *http.Requ
 	}
 }
 
+// @Summary Unassign mission from spy cat
+// @Description Take a mission back from its spy cat. The mission returns to created and its targets keep their notes.
+// @Tags missions
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Mission ID"
+// @Success 200 {object} MissionResponseDoc
+// @Failure 400 {object} ErrorResponseDoc
+// @Failure 401 {object} ErrorResponseDoc
+// @Failure 403 {object} ErrorResponseDoc
+// @Failure 404 {object} ErrorResponseDoc
+// @Failure 409 {object} ErrorResponseDoc
+// @Failure 500 {object} ErrorResponseDoc
+// @Router /missions/{id}/spy-cat [delete]
+func (app *application) unassignMissionHandler(w http.ResponseWriter, r *http.Request) {
+	id, err := app.readIDParam(r, "id")
+	if err != nil {
+		app.notFoundResponse(w, r)
+		return
+	}
+
+	mission, err := app.missionsService.GetMissionByID(r.Context(), id)
+	if err != nil {
+		if errors.Is(err, storage.ErrorModelNotFound) {
+			app.notFoundResponse(w, r)
+		} else {
+			app.serverErrorResponse(w, r, err)
+		}
+		return
+	}
+
+	err = app.missionsService.UnassignMission(r.Context(), mission, app.contextGetAgent(r).Id)
+	if err != nil {
+		switch {
+		case errors.Is(err, model.ErrInvalidTransition):
+			app.conflictResponse(w, r, err)
+		case errors.Is(err, storage.ErrorEditConflict):
+			app.editConflictResponse(w, r)
+		default:
+			app.serverErrorResponse(w, r, err)
+		}
+		return
+	}
+
+	err = app.writeJson(w, http.StatusOK, envelope{"mission": mission})
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+	}
+}
+
+// @Summary Hand off mission to another spy cat
+// @Description Move a mission in progress to another available spy cat. Completed targets stay as they are and the handoff is recorded with the agent who made it.
+// @Tags missions
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Mission ID"
+// @Param handoff body HandOffMissionRequestDoc true "Handoff Details"
+// @Success 200 {object} MissionResponseDoc
+// @Failure 400 {object} ErrorResponseDoc
+// @Failure 401 {object} ErrorResponseDoc
+// @Failure 403 {object} ErrorResponseDoc
+// @Failure 404 {object} ErrorResponseDoc
+// @Failure 409 {object} ErrorResponseDoc
+// @Failure 422 {object} ValidationErrorResponseDoc
+// @Failure 500 {object} ErrorResponseDoc
+// @Router /missions/{id}/handoff [post]
+func (app *application) handOffMissionHandler(w http.ResponseWriter, r *http.Request) {
+	id, err := app.readIDParam(r, "id")
+	if err != nil {
+		app.notFoundResponse(w, r)
+		return
+	}
+
+	var input struct {
+		SpyCatId int64 `json:"spy_cat_id"`
+	}
+
+	err = app.readJSON(w, r, &input)
+	if err != nil {
+		app.badRequestResponse(w, r, err)
+		return
+	}
+
+	v := validator.New()
+	if v.Check(input.SpyCatId > 0, "spy_cat_id", "must be provided"); !v.Valid() {
+		app.failedValidationResponse(w, r, v.Errors)
+		return
+	}
+
+	mission, err := app.missionsService.GetMissionByID(r.Context(), id)
+	if err != nil {
+		if errors.Is(err, storage.ErrorModelNotFound) {
+			app.notFoundResponse(w, r)
+		} else {
+			app.serverErrorResponse(w, r, err)
+		}
+		return
+	}
+
+	spyCat, err := app.spyCatsService.GetById(r.Context(), input.SpyCatId)
+	if err != nil {
+		if errors.Is(err, storage.ErrorModelNotFound) {
+			v.AddError("spy_cat_id", "spy cat not found")
+			app.failedValidationResponse(w, r, v.Errors)
+		} else {
+			app.serverErrorResponse(w, r, err)
+		}
+		return
+	}
+
+	err = app.missionsService.HandOffMission(r.Context(), mission, spyCat, app.contextGetAgent(r).Id)
+	if err != nil {
+		switch {
+		case errors.Is(err, storage.ErrorModelNotFound):
+			app.notFoundResponse(w, r)
+		case errors.Is(err, service.ErrSpyCatIsBusy):
+			app.badRequestResponse(w, r, err)
+		case errors.Is(err, model.ErrInvalidTransition):
+			app.conflictResponse(w, r, err)
+		case errors.Is(err, storage.ErrorEditConflict):
+			app.editConflictResponse(w, r)
+		default:
+			app.serverErrorResponse(w, r, err)
+		}
+		return
+	}
+
+	err = app.writeJson(w, http.StatusOK, envelope{"mission": mission, "spy-cat": spyCat})
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+	}
+}
+
+// @Summary List mission handoffs
+// @Description Get the spy cats a mission was taken from, to whom it was given and by which agent
+// @Tags missions
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Mission ID"
+// @Success 200 {object} MissionHandoffsResponseDoc
+// @Failure 401 {object} ErrorResponseDoc
+// @Failure 403 {object} ErrorResponseDoc
+// @Failure 404 {object} ErrorResponseDoc
+// @Failure 500 {object} ErrorResponseDoc
+// @Router /missions/{id}/handoffs [get]
+func (app *application) listMissionHandoffsHandler(w http.ResponseWriter, r *http.Request) {
+	id, err := app.readIDParam(r, "id")
+	if err != nil {
+		app.notFoundResponse(w, r)
+		return
+	}
+
+	handoffs, err := app.missionsService.GetMissionHandoffs(r.Context(), id)
+	if err != nil {
+		switch {
+		case errors.Is(err, storage.ErrorModelNotFound):
+			app.notFoundResponse(w, r)
+		default:
+			app.serverErrorResponse(w, r, err)
+		}
+		return
+	}
+
+	err = app.writeJson(w, http.StatusOK, envelope{"handoffs": handoffs})
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+	}
+}
+
 // @Summary Create mission target
 // @Description Add a new target to a mission
 // @Tags missions
@@ -278,6 +660,7 @@ func (app *application) assignMissionHandler(w http.ResponseWriter, r *http.Requ
 // @Success 201 {object} TargetResponseDoc
 // @Failure 400 {object} ErrorResponseDoc
 // @Failure 401 {object} ErrorResponseDoc
+// @Failure 403 {object} ErrorResponseDoc
 // @Failure 404 {object} ErrorResponseDoc
 // @Failure 500 {object} ErrorResponseDoc
 // @Router /missions/{id}/targets [post]
@@ -328,7 +711,7 @@ func (app *application) createMissionTargetHandler(w http.ResponseWriter, r *htt
 }
 
 // @Summary Complete mission target
-// @Description Mark a mission target as completed
+// @Description Mark a target of a mission that is not finished as completed
 // @Tags missions
 // @Accept json
 // @Produce json
@@ -336,8 +719,11 @@ func (app *application) createMissionTargetHandler(w http.ResponseWriter, r *htt
 // @Param id path int true "Mission ID"
 // @Param target-id path int true "Target ID"
 // @Success 200 {object} TargetResponseDoc
+// @Failure 400 {object} ErrorResponseDoc
 // @Failure 401 {object} ErrorResponseDoc
+// @Failure 403 {object} ErrorResponseDoc
 // @Failure 404 {object} ErrorResponseDoc
+// @Failure 409 {object} ErrorResponseDoc
 // @Failure 500 {object} ErrorResponseDoc
 // @Router /missions/{id}/targets/{target-id}/complete [patch]
 func (app *application) completeMissionTargetHandler(w http.ResponseWriter, r *http.Request) {
@@ -369,8 +755,14 @@ func (app *application) completeMissionTargetHandler(w http.ResponseWriter, r *h
 		switch {
 		case errors.Is(err, storage.ErrorModelNotFound):
 			app.notFoundResponse(w, r)
+		case errors.Is(err, service.ErrOperationNotAllowedOnCompleted):
+			app.badRequestResponse(w, r, err)
 		case errors.Is(err, service.ErrAccessDenied):
 			app.errorResponse(w, r, http.StatusForbidden, err.Error())
+		case errors.Is(err, model.ErrInvalidTransition):
+			app.conflictResponse(w, r, err)
+		case errors.Is(err, storage.ErrorEditConflict):
+			app.editConflictResponse(w, r)
 		default:
 			app.serverErrorResponse(w, r, err)
 		}
@@ -395,6 +787,7 @@ func (app *application) completeMissionTargetHandler(w http.ResponseWriter, r *h
 // @Success 200 {object} TargetResponseDoc
 // @Failure 400 {object} ErrorResponseDoc
 // @Failure 401 {object} ErrorResponseDoc
+// @Failure 403 {object} ErrorResponseDoc
 // @Failure 404 {object} ErrorResponseDoc
 // @Failure 500 {object} ErrorResponseDoc
 // @Router /missions/{id}/targets/{target-id} [patch]
@@ -464,6 +857,7 @@ func (app *application) updateMissionTargetHandler(w http.ResponseWriter, r *htt
 // @Param target-id path int true "Target ID"
 // @Success 200 {object} MessageResponseDoc
 // @Failure 401 {object} ErrorResponseDoc
+// @Failure 403 {object} ErrorResponseDoc
 // @Failure 404 {object} ErrorResponseDoc
 // @Failure 500 {object} ErrorResponseDoc
 // @Router /missions/{id}/targets/{target-id} [delete]
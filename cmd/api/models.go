@@ -18,7 +18,7 @@ type ErrorResponseDoc struct {
 // swagger:model ValidationErrorResponse
 type ValidationErrorResponseDoc struct {
 	// Map of field names to error messages
-	// Example: {"name": "must be provided", "breed": "invalid breed"}
+	// Example: {"name": "must be provided", "breed": "invalid breed, did you mean: Siamese, Somali, Sphynx"}
 	Error map[string]string `json:"error"`
 }
 
@@ -33,9 +33,78 @@ type MessageResponseDoc struct {
 	Message string `json:"message"`
 }
 
+// HealthcheckResponse represents a liveness check response
+// @Description Liveness check response
+// @Example {"status": "available", "system_info": {"environment": "development", "version": "1.0.0"}}
+//
+// swagger:model HealthcheckResponse
+type HealthcheckResponseDoc struct {
+	// Process status
+	// Example: available
+	Status string `json:"status"`
+	// Environment and version of the running process
+	SystemInfo map[string]string `json:"system_info"`
+}
+
+// ReadinessResponse represents a readiness check response
+// @Description Readiness check response with the state of every dependency
+// @Example {"status": "not ready", "checks": {"server": "up", "database": "up", "breeds": "unavailable"}}
+//
+// swagger:model ReadinessResponse
+type ReadinessResponseDoc struct {
+	// Readiness status (ready, not ready)
+	// Example: ready
+	Status string `json:"status"`
+	// State of every dependency (up, unavailable, shutting down)
+	Checks map[string]string `json:"checks"`
+}
+
+// BreedsResponse represents a list of breeds response
+// @Description Response containing the breeds a spy cat can be of
+// @Example {"breeds": [{"id": "siam", "name": "Siamese", "origin": "Thailand", "temperament": "Active, Agile, Clever", "life_span": "12 - 15"}]}
+//
+// swagger:model BreedsResponse
+type BreedsResponseDoc struct {
+	// List of breeds
+	Breeds []BreedDoc `json:"breeds"`
+}
+
+// BreedResponse represents a breed response
+// @Description Response containing a single breed
+// @Example {"breed": {"id": "siam", "name": "Siamese", "origin": "Thailand", "temperament": "Active, Agile, Clever", "life_span": "12 - 15"}}
+//
+// swagger:model BreedResponse
+type BreedResponseDoc struct {
+	// Breed data
+	Breed BreedDoc `json:"breed"`
+}
+
+// Breed represents a cat breed
+// @Description A cat breed as known to TheCatAPI
+// @Example {"id": "siam", "name": "Siamese", "origin": "Thailand", "temperament": "Active, Agile, Clever", "life_span": "12 - 15"}
+//
+// swagger:model Breed
+type BreedDoc struct {
+	// TheCatAPI breed ID
+	// Example: siam
+	Id string `json:"id"`
+	// Breed name
+	// Example: Siamese
+	Name string `json:"name"`
+	// Country of origin
+	// Example: Thailand
+	Origin string `json:"origin"`
+	// Comma separated temperament traits
+	// Example: Active, Agile, Clever
+	Temperament string `json:"temperament"`
+	// Life span in years
+	// Example: 12 - 15
+	LifeSpan string `json:"life_span"`
+}
+
 // SpyCatResponse represents a spy cat response
 // @Description Response containing a single spy cat
-// @Example {"spy-cat": {"id": 1, "name": "Agent Whiskers", "years_of_experience": 5, "breed": "Siamese", "salary": 50000.00}}
+// @Example {"spy-cat": {"id": 1, "name": "Agent Whiskers", "years_of_experience": 5, "breed": "Siamese", "salary": {"amount": "50000.00", "currency": "USD"}, "created_at": "2023-06-01T10:00:00Z"}}
 //
 // swagger:model SpyCatResponse
 type SpyCatResponseDoc struct {
@@ -44,18 +113,43 @@ type SpyCatResponseDoc struct {
 }
 
 // SpyCatsResponse represents a list of spy cats response
-// @Description Response containing a list of spy cats
-// @Example {"spy-cats": [{"id": 1, "name": "Agent Whiskers", "years_of_experience": 5, "breed": "Siamese", "salary": 50000.00}]}
+// @Description Response containing a page of spy cats with pagination metadata
+// @Example {"spy-cats": [{"id": 1, "name": "Agent Whiskers", "years_of_experience": 5, "breed": "Siamese", "salary": {"amount": "50000.00", "currency": "USD"}, "created_at": "2023-06-01T10:00:00Z"}], "metadata": {"current_page": 1, "page_size": 20, "first_page": 1, "last_page": 1, "total_records": 1}}
 //
 // swagger:model SpyCatsResponse
 type SpyCatsResponseDoc struct {
 	// List of spy cats
 	SpyCats []SpyCatDoc `json:"spy-cats"`
+	// Pagination metadata
+	Metadata MetadataDoc `json:"metadata"`
+}
+
+// Metadata represents pagination metadata
+// @Description Pagination metadata of a list response
+// @Example {"current_page": 1, "page_size": 20, "first_page": 1, "last_page": 1, "total_records": 1}
+//
+// swagger:model Metadata
+type MetadataDoc struct {
+	// Current page number
+	// Example: 1
+	CurrentPage int `json:"current_page"`
+	// Page size
+	// Example: 20
+	PageSize int `json:"page_size"`
+	// First page number
+	// Example: 1
+	FirstPage int `json:"first_page"`
+	// Last page number
+	// Example: 1
+	LastPage int `json:"last_page"`
+	// Total number of records matching the filters
+	// Example: 1
+	TotalRecords int `json:"total_records"`
 }
 
 // SpyCat represents a spy cat
 // @Description Spy cat entity
-// @Example {"id": 1, "name": "Agent Whiskers", "years_of_experience": 5, "breed": "Siamese", "salary": 50000.00}
+// @Example {"id": 1, "name": "Agent Whiskers", "years_of_experience": 5, "breed": "Siamese", "salary": {"amount": "50000.00", "currency": "USD"}, "created_at": "2023-06-01T10:00:00Z"}
 //
 // swagger:model SpyCat
 type SpyCatDoc struct {
@@ -72,13 +166,18 @@ type SpyCatDoc struct {
 	// Example: Siamese
 	Breed string `json:"breed"`
 	// Annual salary
-	// Example: 50000.00
-	Salary float64 `json:"salary"`
+	Salary MoneyDoc `json:"salary"`
+	// Time the spy cat was created, payroll reports charge it from this month on
+	// Example: 2023-06-01T10:00:00Z
+	CreatedAt string `json:"created_at"`
+	// Time the spy cat was archived, absent for active spy cats
+	// Example: 2024-02-01T10:00:00Z
+	DeletedAt string `json:"deleted_at,omitempty"`
 }
 
 // CreateSpyCatRequest represents the request body for creating a spy cat
 // @Description Request body for creating a new spy cat
-// @Example {"name": "Agent Whiskers", "years_of_experience": 5, "breed": "Siamese", "salary": 50000.00, "password": "secretpassword123"}
+// @Example {"name": "Agent Whiskers", "years_of_experience": 5, "breed": "Siamese", "salary": {"amount": "50000.00", "currency": "USD"}, "password": "secretpassword123"}
 //
 // swagger:model CreateSpyCatRequest
 type CreateSpyCatRequestDoc struct {
@@ -88,26 +187,141 @@ type CreateSpyCatRequestDoc struct {
 	// Years of experience
 	// Example: 5
 	YearsOfExperience int `json:"years_of_experience"`
-	// Cat breed
+	// Cat breed name or TheCatAPI ID, matched ignoring case and extra whitespace
 	// Example: Siamese
 	Breed string `json:"breed"`
 	// Annual salary
-	// Example: 50000.00
-	Salary float64 `json:"salary"`
+	Salary MoneyDoc `json:"salary"`
 	// Password for authentication
 	// Example: secretpassword123
 	Password string `json:"password"`
 }
 
-// UpdateSpyCatSalaryRequest represents the request body for updating spy cat salary
-// @Description Request body for updating a spy cat's salary
-// @Example {"salary": 55000.00}
+// UpdateSpyCatRequest represents the request body for updating a spy cat
+// @Description JSON Merge Patch of a spy cat, only the given fields are changed
+// @Example {"name": "Agent Whiskers", "breed": "siamese", "salary": {"amount": "55000.00", "currency": "USD"}}
 //
-// swagger:model UpdateSpyCatSalaryRequest
-type UpdateSpyCatSalaryRequestDoc struct {
-	// New salary amount
-	// Example: 55000.00
-	Salary float64 `json:"salary"`
+// swagger:model UpdateSpyCatRequest
+type UpdateSpyCatRequestDoc struct {
+	// Spy cat name
+	// Example: Agent Whiskers
+	Name string `json:"name,omitempty"`
+	// Years of experience
+	// Example: 6
+	YearsOfExperience int `json:"years_of_experience,omitempty"`
+	// Cat breed name or TheCatAPI ID, matched ignoring case and extra whitespace
+	// Example: Siamese
+	Breed string `json:"breed,omitempty"`
+	// New annual salary
+	Salary *MoneyDoc `json:"salary,omitempty"`
+	// Reason of the salary change, only with salary
+	// Example: annual review
+	SalaryReason string `json:"salary_reason,omitempty"`
+	// Date from which the new salary applies, only with salary, defaults to today, not before the latest salary change
+	// Example: 2024-01-01
+	SalaryEffectiveDate string `json:"salary_effective_date,omitempty"`
+}
+
+// SalaryHistoryResponse represents a salary history response
+// @Description Response containing the salary changes of a spy cat
+// @Example {"salary_history": [{"id": 1, "spy_cat_id": 1, "agent_id": 1, "old_salary": {"amount": "50000.00", "currency": "USD"}, "new_salary": {"amount": "55000.00", "currency": "USD"}, "effective_date": "2024-01-01T00:00:00Z", "reason": "annual review", "created_at": "2023-12-20T10:00:00Z"}]}
+//
+// swagger:model SalaryHistoryResponse
+type SalaryHistoryResponseDoc struct {
+	// List of salary changes
+	SalaryHistory []SalaryChangeDoc `json:"salary_history"`
+}
+
+// SalaryChange represents a salary change
+// @Description A change of the salary of a spy cat
+// @Example {"id": 1, "spy_cat_id": 1, "agent_id": 1, "old_salary": {"amount": "50000.00", "currency": "USD"}, "new_salary": {"amount": "55000.00", "currency": "USD"}, "effective_date": "2024-01-01T00:00:00Z", "reason": "annual review", "created_at": "2023-12-20T10:00:00Z"}
+//
+// swagger:model SalaryChange
+type SalaryChangeDoc struct {
+	// Salary change ID
+	// Example: 1
+	Id int64 `json:"id"`
+	// Spy cat ID
+	// Example: 1
+	SpyCatId int64 `json:"spy_cat_id"`
+	// ID of the agent who changed the salary
+	// Example: 1
+	AgentId int64 `json:"agent_id"`
+	// Annual salary before the change
+	OldSalary MoneyDoc `json:"old_salary"`
+	// Annual salary after the change
+	NewSalary MoneyDoc `json:"new_salary"`
+	// Date from which the new salary applies
+	// Example: 2024-01-01T00:00:00Z
+	EffectiveDate string `json:"effective_date"`
+	// Reason of the change
+	// Example: annual review
+	Reason string `json:"reason"`
+	// Time the change was recorded
+	// Example: 2023-12-20T10:00:00Z
+	CreatedAt string `json:"created_at"`
+}
+
+// PayrollResponse represents a payroll report response
+// @Description Response containing the monthly salary cost per breed and experience band
+// @Example {"payroll": {"from": "2024-01", "to": "2024-01", "rows": [{"month": "2024-01", "breed": "Siamese", "experience_band": "3-5", "spy_cats": 2, "cost": {"amount": "9166.67", "currency": "USD"}}], "totals": [{"amount": "9166.67", "currency": "USD"}]}}
+//
+// swagger:model PayrollResponse
+type PayrollResponseDoc struct {
+	// Payroll report
+	Payroll PayrollDoc `json:"payroll"`
+}
+
+// Payroll represents a payroll report
+// @Description Monthly salary cost per breed and experience band for a range of months
+//
+// swagger:model Payroll
+type PayrollDoc struct {
+	// First month
+	// Example: 2024-01
+	From string `json:"from"`
+	// Last month
+	// Example: 2024-03
+	To string `json:"to"`
+	// Cost per month, breed, experience band and currency
+	Rows []PayrollRowDoc `json:"rows"`
+	// Total cost of the range per currency
+	Totals []MoneyDoc `json:"totals"`
+}
+
+// PayrollRow represents a payroll report row
+// @Description Salary cost of the spy cats of a breed and experience band in a month
+//
+// swagger:model PayrollRow
+type PayrollRowDoc struct {
+	// Month
+	// Example: 2024-01
+	Month string `json:"month"`
+	// Breed
+	// Example: Siamese
+	Breed string `json:"breed"`
+	// Experience band (0-2, 3-5, 6-10, 11+ years)
+	// Example: 3-5
+	ExperienceBand string `json:"experience_band"`
+	// Number of spy cats
+	// Example: 2
+	SpyCats int `json:"spy_cats"`
+	// Salary cost of the month
+	Cost MoneyDoc `json:"cost"`
+}
+
+// Money represents an amount of money
+// @Description Amount of money in an ISO 4217 currency (EUR, GBP, JPY, UAH, USD)
+// @Example {"amount": "50000.00", "currency": "USD"}
+//
+// swagger:model Money
+type MoneyDoc struct {
+	// Decimal amount with at most as many fractional digits as the currency has
+	// Example: 50000.00
+	Amount string `json:"amount"`
+	// ISO 4217 currency code
+	// Example: USD
+	Currency string `json:"currency"`
 }
 
 // MissionResponse represents a mission response
@@ -121,13 +335,29 @@ type MissionResponseDoc struct {
 }
 
 // MissionsResponse represents a list of missions response
-// @Description Response containing a list of missions
-// @Example {"missions": [{"id": 1, "state": "created", "assigned_cat_id": 1, "targets": []}]}
+// @Description Response containing a page of missions with cursor metadata
+// @Example {"missions": [{"id": 1, "state": "created", "assigned_cat_id": 1, "targets": []}], "metadata": {"next_cursor": "MQ", "limit": 20}}
 //
 // swagger:model MissionsResponse
 type MissionsResponseDoc struct {
 	// List of missions
 	Missions []MissionDoc `json:"missions"`
+	// Cursor metadata
+	Metadata CursorMetadataDoc `json:"metadata"`
+}
+
+// CursorMetadata represents keyset pagination metadata
+// @Description Keyset pagination metadata of a list response
+// @Example {"next_cursor": "MQ", "limit": 20}
+//
+// swagger:model CursorMetadata
+type CursorMetadataDoc struct {
+	// Cursor of the next page, omitted on the last page
+	// Example: MQ
+	NextCursor string `json:"next_cursor"`
+	// Page size
+	// Example: 20
+	Limit int `json:"limit"`
 }
 
 // Mission represents a mission
@@ -139,9 +369,12 @@ type MissionDoc struct {
 	// Unique identifier
 	// Example: 1
 	ID int64 `json:"id"`
-	// Mission state (created, in_progress, completed)
+	// Mission state (created, in_progress, completed, aborted, failed)
 	// Example: created
 	State string `json:"state"`
+	// Reason the mission was aborted or failed
+	// Example: the target has gone into hiding
+	Reason string `json:"reason,omitempty"`
 	// ID of assigned spy cat
 	// Example: 1
 	AssignedCatID int64 `json:"assigned_cat_id"`
@@ -159,6 +392,64 @@ type CreateMissionRequestDoc struct {
 	Targets []CreateTargetRequestDoc `json:"targets"`
 }
 
+// MissionReasonRequest represents the request body for aborting or failing a mission
+// @Description Request body with the reason a mission is aborted or failed
+// @Example {"reason": "the target has gone into hiding"}
+//
+// swagger:model MissionReasonRequest
+type MissionReasonRequestDoc struct {
+	// Reason of the abort or failure
+	// Example: the target has gone into hiding
+	Reason string `json:"reason"`
+}
+
+// HandOffMissionRequest represents the request body for handing off a mission
+// @Description Request body for handing off a mission to another spy cat
+// @Example {"spy_cat_id": 2}
+//
+// swagger:model HandOffMissionRequest
+type HandOffMissionRequestDoc struct {
+	// ID of the spy cat taking over the mission
+	// Example: 2
+	SpyCatID int64 `json:"spy_cat_id"`
+}
+
+// MissionHandoffsResponse represents a mission handoffs response
+// @Description Response containing the handoffs of a mission
+// @Example {"handoffs": [{"id": 1, "mission_id": 1, "from_spy_cat_id": 1, "to_spy_cat_id": 2, "agent_id": 1, "created_at": "2024-01-01T10:00:00Z"}]}
+//
+// swagger:model MissionHandoffsResponse
+type MissionHandoffsResponseDoc struct {
+	// List of handoffs
+	Handoffs []MissionHandoffDoc `json:"handoffs"`
+}
+
+// MissionHandoff represents a mission handoff
+// @Description A change of the spy cat assigned to a mission
+// @Example {"id": 1, "mission_id": 1, "from_spy_cat_id": 1, "to_spy_cat_id": 2, "agent_id": 1, "created_at": "2024-01-01T10:00:00Z"}
+//
+// swagger:model MissionHandoff
+type MissionHandoffDoc struct {
+	// Handoff ID
+	// Example: 1
+	ID int64 `json:"id"`
+	// Mission ID
+	// Example: 1
+	MissionID int64 `json:"mission_id"`
+	// Spy cat the mission was taken from
+	// Example: 1
+	FromSpyCatID int64 `json:"from_spy_cat_id"`
+	// Spy cat the mission was given to, absent when it was unassigned
+	// Example: 2
+	ToSpyCatID int64 `json:"to_spy_cat_id,omitempty"`
+	// Agent who made the handoff
+	// Example: 1
+	AgentID int64 `json:"agent_id"`
+	// Time of the handoff
+	// Example: 2024-01-01T10:00:00Z
+	CreatedAt string `json:"created_at"`
+}
+
 // Target represents a mission target
 // @Description Mission target entity
 // @Example {"id": 1, "mission_id": 1, "name": "Dr. Evil", "country": "Switzerland", "notes": "Target spotted at secret lair", "state": "created"}
@@ -232,7 +523,7 @@ type AgentResponseDoc struct {
 
 // Agent represents an agent
 // @Description Agent entity
-// @Example {"id": 1, "name": "Agent Smith"}
+// @Example {"id": 1, "name": "Agent Smith", "role": "analyst"}
 //
 // swagger:model Agent
 type AgentDoc struct {
@@ -242,14 +533,20 @@ type AgentDoc struct {
 	// Agent name
 	// Example: Agent Smith
 	Name string `json:"name"`
+	// Agent role (admin, handler, analyst)
+	// Example: analyst
+	Role string `json:"role"`
 }
 
 // CreateAgentRequest represents the request body for creating an agent
-// @Description Request body for creating a new agent
-// @Example {"name": "Agent Smith", "password": "agentpassword123"}
+// @Description Request body for redeeming an invitation and creating a new agent
+// @Example {"token": "ABCDEFGHIJKLMNOPQRSTUVWXYZ", "name": "Agent Smith", "password": "agentpassword123"}
 //
 // swagger:model CreateAgentRequest
 type CreateAgentRequestDoc struct {
+	// Invitation token
+	// Example: ABCDEFGHIJKLMNOPQRSTUVWXYZ
+	Token string `json:"token"`
 	// Agent name
 	// Example: Agent Smith
 	Name string `json:"name"`
@@ -258,6 +555,60 @@ type CreateAgentRequestDoc struct {
 	Password string `json:"password"`
 }
 
+// InvitationTokenResponse represents an invitation token response
+// @Description Response containing an agent invitation token
+// @Example {"invitation_token": {"token": "ABCDEFGHIJKLMNOPQRSTUVWXYZ", "expiry": "2024-01-01T00:00:00Z"}}
+//
+// swagger:model InvitationTokenResponse
+type InvitationTokenResponseDoc struct {
+	// Invitation token data
+	InvitationToken TokenDoc `json:"invitation_token"`
+}
+
+// ChangeAgentRoleRequest represents the request body for changing an agent role
+// @Description Request body for changing an agent role
+// @Example {"role": "handler"}
+//
+// swagger:model ChangeAgentRoleRequest
+type ChangeAgentRoleRequestDoc struct {
+	// Agent role (admin, handler, analyst)
+	// Example: handler
+	Role string `json:"role"`
+}
+
+// LockoutsResponse represents a login lockouts list response
+// @Description Response containing the currently locked out names
+// @Example {"lockouts": [{"user_type": "agent", "name": "Agent Smith", "failures": 5, "locked_until": "2024-01-01T00:00:30Z", "last_failure_at": "2024-01-01T00:00:00Z"}]}
+//
+// swagger:model LockoutsResponse
+type LockoutsResponseDoc struct {
+	// List of lockouts
+	Lockouts []LockoutDoc `json:"lockouts"`
+}
+
+// Lockout represents a login lockout
+// @Description Failed logins of a name
+// @Example {"user_type": "agent", "name": "Agent Smith", "failures": 5, "locked_until": "2024-01-01T00:00:30Z", "last_failure_at": "2024-01-01T00:00:00Z"}
+//
+// swagger:model Lockout
+type LockoutDoc struct {
+	// User type (spy-cat, agent)
+	// Example: agent
+	UserType string `json:"user_type"`
+	// Login name
+	// Example: Agent Smith
+	Name string `json:"name"`
+	// Number of consecutive failed logins
+	// Example: 5
+	Failures int `json:"failures"`
+	// Time until which logins are rejected
+	// Example: 2024-01-01T00:00:30Z
+	LockedUntil string `json:"locked_until"`
+	// Time of the last failed login
+	// Example: 2024-01-01T00:00:00Z
+	LastFailureAt string `json:"last_failure_at"`
+}
+
 // TokenResponse represents an authentication token response
 // @Description Response containing an authentication token
 // @Example {"authentication_token": {"plaintext": "ABCDEF123456", "user_id": 1, "expiry": "2024-01-01T00:00:00Z", "scope": "authentication"}}
@@ -301,3 +652,41 @@ type AuthenticationRequestDoc struct {
 	// Example: password123
 	Password string `json:"password"`
 }
+
+// ChangePasswordRequest represents the request body for changing own password
+// @Description Request body for changing the password of the authenticated user
+// @Example {"current_password": "password123", "password": "newpassword123"}
+//
+// swagger:model ChangePasswordRequest
+type ChangePasswordRequestDoc struct {
+	// Current password
+	// Example: password123
+	CurrentPassword string `json:"current_password"`
+	// New password
+	// Example: newpassword123
+	Password string `json:"password"`
+}
+
+// PasswordResetTokenResponse represents a password reset token response
+// @Description Response containing a password reset token
+// @Example {"password_reset_token": {"token": "ABCDEFGHIJKLMNOPQRSTUVWXYZ", "expiry": "2024-01-01T00:00:00Z"}}
+//
+// swagger:model PasswordResetTokenResponse
+type PasswordResetTokenResponseDoc struct {
+	// Password reset token data
+	PasswordResetToken TokenDoc `json:"password_reset_token"`
+}
+
+// ResetPasswordRequest represents the request body for resetting a password
+// @Description Request body for setting a new password with a reset token
+// @Example {"token": "ABCDEFGHIJKLMNOPQRSTUVWXYZ", "password": "newpassword123"}
+//
+// swagger:model ResetPasswordRequest
+type ResetPasswordRequestDoc struct {
+	// Password reset token
+	// Example: ABCDEFGHIJKLMNOPQRSTUVWXYZ
+	Token string `json:"token"`
+	// New password
+	// Example: newpassword123
+	Password string `json:"password"`
+}
@@ -0,0 +1,219 @@
+package main
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/m1crogravity/spy-cat-agency/internal/model"
+	"github.com/m1crogravity/spy-cat-agency/internal/storage"
+	"github.com/m1crogravity/spy-cat-agency/internal/validator"
+)
+
+// @Summary Change own password
+// @Description Change the password of the authenticated spy cat or agent
+// @Tags me
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param password body ChangePasswordRequestDoc true "Current and new password"
+// @Success 200 {object} MessageResponseDoc
+// @Failure 400 {object} ErrorResponseDoc
+// @Failure 401 {object} ErrorResponseDoc
+// @Failure 422 {object} ValidationErrorResponseDoc
+// @Failure 429 {object} ErrorResponseDoc
+// @Failure 500 {object} ErrorResponseDoc
+// @Router /me/password [put]
+func (app *application) updateMyPasswordHandler(w http.ResponseWriter, r *http.Request) {
+	var input struct {
+		CurrentPassword string `json:"current_password"`
+		Password        string `json:"password"`
+	}
+
+	err := app.readJSON(w, r, &input)
+	if err != nil {
+		app.badRequestResponse(w, r, err)
+		return
+	}
+
+	v := validator.New()
+	v.Check(input.CurrentPassword != "", "current_password", "must be provided")
+	model.ValidatePasswordPlaintext(v, input.Password)
+
+	if !v.Valid() {
+		app.failedValidationResponse(w, r, v.Errors)
+		return
+	}
+
+	spyCat := app.contextGetSpyCat(r)
+	agent := app.contextGetAgent(r)
+
+	password := &agent.Password
+	if !spyCat.IsAnonymous() {
+		password = &spyCat.Password
+	}
+
+	match, err := password.Matches(input.CurrentPassword)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+		return
+	}
+
+	if !match {
+		v.AddError("current_password", "does not match")
+		app.failedValidationResponse(w, r, v.Errors)
+		return
+	}
+
+	err = password.Set(input.Password)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+		return
+	}
+
+	if !spyCat.IsAnonymous() {
+		err = app.spyCatsService.UpdatePassword(r.Context(), spyCat)
+	} else {
+		err = app.agentsService.UpdatePassword(r.Context(), agent)
+	}
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+		return
+	}
+
+	err = app.writeJson(w, http.StatusOK, envelope{"message": "password successfully changed"})
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+	}
+}
+
+// @Summary Create spy cat password reset token
+// @Description Issue a single-use token which allows the spy cat to set a new password
+// @Tags spy-cats
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Spy Cat ID"
+// @Success 201 {object} PasswordResetTokenResponseDoc
+// @Failure 401 {object} ErrorResponseDoc
+// @Failure 403 {object} ErrorResponseDoc
+// @Failure 404 {object} ErrorResponseDoc
+// @Failure 500 {object} ErrorResponseDoc
+// @Router /spy-cats/{id}/password-reset [post]
+func (app *application) createSpyCatPasswordResetTokenHandler(w http.ResponseWriter, r *http.Request) {
+	id, err := app.readIDParam(r, "id")
+	if err != nil {
+		app.notFoundResponse(w, r)
+		return
+	}
+
+	spyCat, err := app.spyCatsService.GetById(r.Context(), id)
+	if err != nil {
+		switch {
+		case errors.Is(err, storage.ErrorModelNotFound):
+			app.notFoundResponse(w, r)
+		default:
+			app.serverErrorResponse(w, r, err)
+		}
+		return
+	}
+
+	app.createPasswordResetToken(w, r, spyCat.Id, model.SpyCatUserType)
+}
+
+// @Summary Create agent password reset token
+// @Description Issue a single-use token which allows the agent to set a new password
+// @Tags agents
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Agent ID"
+// @Success 201 {object} PasswordResetTokenResponseDoc
+// @Failure 401 {object} ErrorResponseDoc
+// @Failure 403 {object} ErrorResponseDoc
+// @Failure 404 {object} ErrorResponseDoc
+// @Failure 500 {object} ErrorResponseDoc
+// @Router /agents/{id}/password-reset [post]
+func (app *application) createAgentPasswordResetTokenHandler(w http.ResponseWriter, r *http.Request) {
+	id, err := app.readIDParam(r, "id")
+	if err != nil {
+		app.notFoundResponse(w, r)
+		return
+	}
+
+	agent, err := app.agentsService.GetById(r.Context(), id)
+	if err != nil {
+		switch {
+		case errors.Is(err, storage.ErrorModelNotFound):
+			app.notFoundResponse(w, r)
+		default:
+			app.serverErrorResponse(w, r, err)
+		}
+		return
+	}
+
+	app.createPasswordResetToken(w, r, agent.Id, model.AgentUserType)
+}
+
+func (app *application) createPasswordResetToken(w http.ResponseWriter, r *http.Request, userID int64, userType model.UserType) {
+	token, err := app.tokensService.Create(r.Context(), userID, userType, app.config.tokens.passwordResetTtl, model.ScopePasswordReset)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+		return
+	}
+
+	err = app.writeJson(w, http.StatusCreated, envelope{"password_reset_token": token})
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+	}
+}
+
+// @Summary Reset password
+// @Description Set a new password using a password reset token. All existing tokens of the user are revoked.
+// @Tags authentication
+// @Accept json
+// @Produce json
+// @Param reset body ResetPasswordRequestDoc true "Reset token and new password"
+// @Success 200 {object} MessageResponseDoc
+// @Failure 400 {object} ErrorResponseDoc
+// @Failure 422 {object} ValidationErrorResponseDoc
+// @Failure 429 {object} ErrorResponseDoc
+// @Failure 500 {object} ErrorResponseDoc
+// @Router /tokens/password-reset [put]
+func (app *application) resetPasswordHandler(w http.ResponseWriter, r *http.Request) {
+	var input struct {
+		Token    string `json:"token"`
+		Password string `json:"password"`
+	}
+
+	err := app.readJSON(w, r, &input)
+	if err != nil {
+		app.badRequestResponse(w, r, err)
+		return
+	}
+
+	v := validator.New()
+	model.ValidateTokenPlaintext(v, input.Token)
+	model.ValidatePasswordPlaintext(v, input.Password)
+
+	if !v.Valid() {
+		app.failedValidationResponse(w, r, v.Errors)
+		return
+	}
+
+	err = app.tokensService.RedeemPasswordReset(r.Context(), input.Token, input.Password)
+	if err != nil {
+		switch {
+		case errors.Is(err, storage.ErrorModelNotFound):
+			v.AddError("token", "invalid or expired password reset token")
+			app.failedValidationResponse(w, r, v.Errors)
+		default:
+			app.serverErrorResponse(w, r, err)
+		}
+		return
+	}
+
+	err = app.writeJson(w, http.StatusOK, envelope{"message": "your password was successfully reset"})
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+	}
+}
@@ -0,0 +1,167 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+type bucket struct {
+	tokens   float64
+	lastSeen time.Time
+}
+
+// rateLimiter is a set of token buckets keyed by client. Every bucket holds up
+// to burst tokens and is refilled with rps tokens per second.
+type rateLimiter struct {
+	mu      sync.Mutex
+	rps     float64
+	burst   float64
+	buckets map[string]*bucket
+}
+
+func newRateLimiter(rps float64, burst int) *rateLimiter {
+	return &rateLimiter{
+		rps:     rps,
+		burst:   float64(burst),
+		buckets: make(map[string]*bucket),
+	}
+}
+
+// allow takes a token from the bucket of key. When the bucket is empty it
+// reports how long the client has to wait for the next token.
+func (l *rateLimiter) allow(key string, now time.Time) (bool, time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	b, ok := l.buckets[key]
+	if !ok {
+		b = &bucket{tokens: l.burst, lastSeen: now}
+		l.buckets[key] = b
+	}
+
+	b.tokens = math.Min(l.burst, b.tokens+now.Sub(b.lastSeen).Seconds()*l.rps)
+	b.lastSeen = now
+
+	if b.tokens >= 1 {
+		b.tokens--
+		return true, 0
+	}
+
+	wait := time.Duration((1 - b.tokens) / l.rps * float64(time.Second))
+	return false, wait
+}
+
+// evict removes buckets which have not been used since before.
+func (l *rateLimiter) evict(before time.Time) int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	evicted := 0
+	for key, b := range l.buckets {
+		if b.lastSeen.Before(before) {
+			delete(l.buckets, key)
+			evicted++
+		}
+	}
+
+	return evicted
+}
+
+// limiters holds the rate limiter of every route group. A nil limiter lets all
+// requests through. The ip limiter runs before authentication, so requests
+// with made-up tokens can't hit the database unthrottled.
+type limiters struct {
+	ip     *rateLimiter
+	global *rateLimiter
+	auth   *rateLimiter
+}
+
+func newLimiters(cfg config) (limiters, error) {
+	if !cfg.limiter.enabled {
+		return limiters{}, nil
+	}
+
+	if cfg.limiter.rps <= 0 || cfg.limiter.authRps <= 0 || cfg.limiter.ipRps <= 0 {
+		return limiters{}, errors.New("rate limiter rps must be greater than zero")
+	}
+	if cfg.limiter.burst < 1 || cfg.limiter.authBurst < 1 || cfg.limiter.ipBurst < 1 {
+		return limiters{}, errors.New("rate limiter burst must be at least one")
+	}
+
+	return limiters{
+		ip:     newRateLimiter(cfg.limiter.ipRps, cfg.limiter.ipBurst),
+		global: newRateLimiter(cfg.limiter.rps, cfg.limiter.burst),
+		auth:   newRateLimiter(cfg.limiter.authRps, cfg.limiter.authBurst),
+	}, nil
+}
+
+func (ls limiters) all() []*rateLimiter {
+	var all []*rateLimiter
+	for _, l := range []*rateLimiter{ls.ip, ls.global, ls.auth} {
+		if l != nil {
+			all = append(all, l)
+		}
+	}
+	return all
+}
+
+// rateLimitKey identifies the client of the request: an authenticated agent or
+// spy cat by its id, anybody else by IP address.
+func (app *application) rateLimitKey(r *http.Request) string {
+	if agent := app.contextGetAgent(r); !agent.IsAnonymous() {
+		return fmt.Sprintf("agent:%d", agent.Id)
+	}
+	if spyCat := app.contextGetSpyCat(r); !spyCat.IsAnonymous() {
+		return fmt.Sprintf("spy-cat:%d", spyCat.Id)
+	}
+
+	return ipRateLimitKey(r)
+}
+
+func ipRateLimitKey(r *http.Request) string {
+	ip, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		ip = r.RemoteAddr
+	}
+	return "ip:" + ip
+}
+
+func (app *application) rateLimit(limiter *rateLimiter, next http.Handler) http.Handler {
+	return app.rateLimitBy(limiter, app.rateLimitKey, next)
+}
+
+// rateLimitByIP limits requests by client IP only. Unlike rateLimit it does not
+// need the authenticated user in the context.
+func (app *application) rateLimitByIP(limiter *rateLimiter, next http.Handler) http.Handler {
+	return app.rateLimitBy(limiter, ipRateLimitKey, next)
+}
+
+func (app *application) rateLimitBy(limiter *rateLimiter, key func(*http.Request) string, next http.Handler) http.Handler {
+	if limiter == nil {
+		return next
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		allowed, wait := limiter.allow(key(r), time.Now())
+		if !allowed {
+			app.rateLimitExceededResponse(w, r, wait)
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+func (app *application) rateLimitFunc(limiter *rateLimiter, next http.HandlerFunc) http.HandlerFunc {
+	return app.rateLimit(limiter, next).ServeHTTP
+}
+
+func retryAfterSeconds(wait time.Duration) string {
+	return strconv.Itoa(int(math.Ceil(wait.Seconds())))
+}
@@ -0,0 +1,137 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+)
+
+const redactedValue = "[REDACTED]"
+
+// defaultRedactFields lists every JSON key of the API that carries a secret.
+var defaultRedactFields = []string{"password", "current_password", "token"}
+
+type bodyLogPolicy string
+
+const (
+	bodyLogOff       bodyLogPolicy = "off"
+	bodyLogTruncated bodyLogPolicy = "truncated"
+	bodyLogFull      bodyLogPolicy = "full"
+)
+
+func parseBodyLogPolicy(s string) (bodyLogPolicy, error) {
+	switch policy := bodyLogPolicy(s); policy {
+	case bodyLogOff, bodyLogTruncated, bodyLogFull:
+		return policy, nil
+	default:
+		return "", fmt.Errorf("unknown body logging policy %q", s)
+	}
+}
+
+// bodyLogRule overrides the default body logging policy for requests whose
+// path starts with prefix. An empty method or policy means "any" and
+// "default" respectively.
+type bodyLogRule struct {
+	method   string
+	prefix   string
+	request  bodyLogPolicy
+	response bodyLogPolicy
+}
+
+var bodyLogRules = []bodyLogRule{
+	{prefix: "/swagger", request: bodyLogOff, response: bodyLogOff},
+	{prefix: "/metrics", request: bodyLogOff, response: bodyLogOff},
+	{prefix: "/v1/healthcheck", request: bodyLogOff, response: bodyLogOff},
+	{prefix: "/v1/readyz", request: bodyLogOff, response: bodyLogOff},
+	{method: http.MethodGet, prefix: "/v1/spy-cats", response: bodyLogTruncated},
+	{method: http.MethodGet, prefix: "/v1/missions", response: bodyLogTruncated},
+}
+
+type redactor struct {
+	fields      *regexp.Regexp
+	headers     []string
+	maxBodySize int
+	policy      bodyLogPolicy
+	rules       []bodyLogRule
+}
+
+func newRedactor(fields []string, maxBodySize int, policy bodyLogPolicy) *redactor {
+	rd := &redactor{
+		headers:     []string{"Authorization", "Cookie", "Set-Cookie", "X-Api-Key"},
+		maxBodySize: maxBodySize,
+		policy:      policy,
+		rules:       bodyLogRules,
+	}
+
+	quoted := make([]string, 0, len(fields))
+	for _, field := range fields {
+		if field = strings.TrimSpace(field); field != "" {
+			quoted = append(quoted, regexp.QuoteMeta(field))
+		}
+	}
+	if len(quoted) > 0 {
+		// Matches string and scalar values of the configured keys. The closing
+		// quote is optional, so a value cut in half by truncation is masked too.
+		rd.fields = regexp.MustCompile(`"(` + strings.Join(quoted, "|") + `)"\s*:\s*(?:"(?:[^"\\]|\\.)*"?|[^,}\]\s]+)`)
+	}
+
+	return rd
+}
+
+func (rd *redactor) policies(r *http.Request) (request, response bodyLogPolicy) {
+	request, response = rd.policy, rd.policy
+	for _, rule := range rd.rules {
+		if rule.method != "" && rule.method != r.Method {
+			continue
+		}
+		if !strings.HasPrefix(r.URL.Path, rule.prefix) {
+			continue
+		}
+		if rule.request != "" {
+			request = rule.request
+		}
+		if rule.response != "" {
+			response = rule.response
+		}
+		break
+	}
+
+	return request, response
+}
+
+// bodyLimit returns how many bytes of a body should be kept for logging, or
+// -1 when the body should be kept whole.
+func (rd *redactor) bodyLimit(policy bodyLogPolicy) int {
+	switch policy {
+	case bodyLogOff:
+		return 0
+	case bodyLogFull:
+		return -1
+	default:
+		return rd.maxBodySize
+	}
+}
+
+func (rd *redactor) body(body []byte, totalSize int) string {
+	s := string(body)
+	if rd.fields != nil {
+		s = rd.fields.ReplaceAllString(s, `"$1":"`+redactedValue+`"`)
+	}
+	if totalSize > len(body) {
+		s += fmt.Sprintf("...(%d bytes truncated)", totalSize-len(body))
+	}
+
+	return s
+}
+
+func (rd *redactor) header(header http.Header) http.Header {
+	redacted := header.Clone()
+	for _, name := range rd.headers {
+		if redacted.Get(name) != "" {
+			redacted.Set(name, redactedValue)
+		}
+	}
+
+	return redacted
+}
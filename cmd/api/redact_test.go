@@ -0,0 +1,165 @@
+package main
+
+import (
+	"bytes"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestRedactorBody(t *testing.T) {
+	tc := []struct {
+		name      string
+		body      string
+		totalSize int
+		expected  string
+	}{
+		{
+			name:     "top level key",
+			body:     `{"name":"Tom","password":"secret"}`,
+			expected: `{"name":"Tom","password":"[REDACTED]"}`,
+		},
+		{
+			name:     "nested key",
+			body:     `{"authentication_token":{"token":"ABC123","expiry":"2030-01-01T00:00:00Z"}}`,
+			expected: `{"authentication_token":{"token":"[REDACTED]","expiry":"2030-01-01T00:00:00Z"}}`,
+		},
+		{
+			name:     "key inside an array",
+			body:     `[{"token":"a"},{"token":"b"}]`,
+			expected: `[{"token":"[REDACTED]"},{"token":"[REDACTED]"}]`,
+		},
+		{
+			name:     "whitespace around the colon",
+			body:     "{\"password\" :\n \"secret\"}",
+			expected: `{"password":"[REDACTED]"}`,
+		},
+		{
+			name:     "escaped quotes",
+			body:     `{"password":"se\"cr\\\"et","name":"Tom"}`,
+			expected: `{"password":"[REDACTED]","name":"Tom"}`,
+		},
+		{
+			name:     "number value",
+			body:     `{"password":12345,"name":"Tom"}`,
+			expected: `{"password":"[REDACTED]","name":"Tom"}`,
+		},
+		{
+			name:     "boolean value",
+			body:     `{"token":true}`,
+			expected: `{"token":"[REDACTED]"}`,
+		},
+		{
+			name:     "null value",
+			body:     `{"token":null}`,
+			expected: `{"token":"[REDACTED]"}`,
+		},
+		{
+			name:      "value cut by truncation",
+			body:      `{"name":"Tom","password":"sec`,
+			totalSize: 40,
+			expected:  `{"name":"Tom","password":"[REDACTED]"...(11 bytes truncated)`,
+		},
+		{
+			name:      "truncated body without secrets",
+			body:      `{"name":"To`,
+			totalSize: 15,
+			expected:  `{"name":"To...(4 bytes truncated)`,
+		},
+		{
+			name:     "keys are matched exactly",
+			body:     `{"password_hint":"cat","tokens":3,"mypassword":"x"}`,
+			expected: `{"password_hint":"cat","tokens":3,"mypassword":"x"}`,
+		},
+		{
+			name:     "values are not matched",
+			body:     `{"name":"password"}`,
+			expected: `{"name":"password"}`,
+		},
+	}
+
+	rd := newRedactor([]string{"password", "token"}, 1024, bodyLogFull)
+	for _, tt := range tc {
+		t.Run(tt.name, func(t *testing.T) {
+			body := rd.body([]byte(tt.body), tt.totalSize)
+			if body != tt.expected {
+				t.Fatalf("expected %s, got %s", tt.expected, body)
+			}
+		})
+	}
+}
+
+func TestRedactorBodyWithoutFields(t *testing.T) {
+	rd := newRedactor([]string{" ", ""}, 1024, bodyLogFull)
+	body := rd.body([]byte(`{"password":"secret"}`), 0)
+	if body != `{"password":"secret"}` {
+		t.Fatalf("expected the body unchanged, got %s", body)
+	}
+}
+
+func TestRedactorHeader(t *testing.T) {
+	rd := newRedactor(nil, 1024, bodyLogFull)
+	header := http.Header{}
+	header.Set("Authorization", "Bearer ABC123")
+	header.Set("Content-Type", "application/json")
+
+	redacted := rd.header(header)
+	if redacted.Get("Authorization") != redactedValue {
+		t.Fatalf("expected the authorization header to be redacted, got %s", redacted.Get("Authorization"))
+	}
+	if redacted.Get("Content-Type") != "application/json" {
+		t.Fatal("expected the content type header to be kept")
+	}
+	if header.Get("Authorization") != "Bearer ABC123" {
+		t.Fatal("expected the original header to be left alone")
+	}
+}
+
+func TestRedactorPolicies(t *testing.T) {
+	tc := []struct {
+		method   string
+		path     string
+		request  bodyLogPolicy
+		response bodyLogPolicy
+	}{
+		{http.MethodGet, "/v1/healthcheck", bodyLogOff, bodyLogOff},
+		{http.MethodGet, "/v1/spy-cats", bodyLogFull, bodyLogTruncated},
+		{http.MethodPost, "/v1/spy-cats", bodyLogFull, bodyLogFull},
+		{http.MethodPost, "/v1/tokens/authentication", bodyLogFull, bodyLogFull},
+	}
+
+	rd := newRedactor(nil, 1024, bodyLogFull)
+	for _, tt := range tc {
+		t.Run(tt.method+" "+tt.path, func(t *testing.T) {
+			request, response := rd.policies(httptest.NewRequest(tt.method, tt.path, nil))
+			if request != tt.request || response != tt.response {
+				t.Fatalf("expected %s/%s, got %s/%s", tt.request, tt.response, request, response)
+			}
+		})
+	}
+}
+
+func TestLogRequestResponseRedactsPasswords(t *testing.T) {
+	var logs bytes.Buffer
+	app := &application{
+		logger:   slog.New(slog.NewTextHandler(&logs, nil)),
+		redactor: newRedactor(defaultRedactFields, 1024, bodyLogFull),
+		metrics:  newMetrics(),
+	}
+	handler := app.logRequestResponse(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	body := `{"current_password":"old-secret","password":"new-secret"}`
+	r := httptest.NewRequest(http.MethodPut, "/v1/me/password", strings.NewReader(body))
+	handler.ServeHTTP(httptest.NewRecorder(), r)
+
+	if strings.Contains(logs.String(), "secret") {
+		t.Fatalf("password was logged: %s", logs.String())
+	}
+	if !strings.Contains(logs.String(), `\"current_password\":\"[REDACTED]\"`) {
+		t.Fatalf("expected the current password to be redacted: %s", logs.String())
+	}
+}
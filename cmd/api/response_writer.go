@@ -7,8 +7,11 @@ import (
 
 type responseWriter struct {
 	http.ResponseWriter
+	route         string
 	statusCode    int
 	body          bytes.Buffer
+	bodyLimit     int
+	bodySize      int
 	headerWritten bool
 }
 
@@ -25,6 +28,12 @@ func (rw *responseWriter) Write(b []byte) (int, error) {
 		rw.statusCode = http.StatusOK
 		rw.headerWritten = true
 	}
-	rw.body.Write(b)
+	rw.bodySize += len(b)
+	switch {
+	case rw.bodyLimit < 0:
+		rw.body.Write(b)
+	case rw.body.Len() < rw.bodyLimit:
+		rw.body.Write(b[:min(len(b), rw.bodyLimit-rw.body.Len())])
+	}
 	return rw.ResponseWriter.Write(b)
 }
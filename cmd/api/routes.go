@@ -4,43 +4,103 @@ import (
 	"net/http"
 
 	"github.com/julienschmidt/httprouter"
+	"github.com/m1crogravity/spy-cat-agency/internal/model"
 	httpSwagger "github.com/swaggo/http-swagger"
 )
 
+// instrumentedRouter records the pattern of the matched route in the responseWriter, so
+// that metrics can be labelled with it instead of the raw path.
+type instrumentedRouter struct {
+	*httprouter.Router
+}
+
+func (rt instrumentedRouter) Handler(method, path string, handler http.Handler) {
+	rt.Router.Handler(method, path, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if rw, ok := w.(*responseWriter); ok {
+			rw.route = path
+		}
+		handler.ServeHTTP(w, r)
+	}))
+}
+
+func (rt instrumentedRouter) HandlerFunc(method, path string, handler http.HandlerFunc) {
+	rt.Handler(method, path, handler)
+}
+
 func (app *application) routes() http.Handler {
-	router := httprouter.New()
+	router := instrumentedRouter{httprouter.New()}
 
 	router.NotFound = http.HandlerFunc(app.notFoundResponse)
 	router.MethodNotAllowed = http.HandlerFunc(app.methodNotAllowedResponse)
 
-	router.HandlerFunc(http.MethodGet, "/v1/spy-cats", app.requireAgent(app.listSpyCatHandler))
-	router.HandlerFunc(http.MethodPost, "/v1/spy-cats", app.requireAgent(app.createSpyCatHandler))
-	router.HandlerFunc(http.MethodGet, "/v1/spy-cats/:id", app.requireAgent(app.getSpyCatHandler))
-	router.HandlerFunc(http.MethodDelete, "/v1/spy-cats/:id", app.requireAgent(app.deleteSpyCatHandler))
-	router.HandlerFunc(http.MethodPatch, "/v1/spy-cats/:id", app.requireAgent(app.updateSpyCatHandler))
-
-	router.HandlerFunc(http.MethodPost, "/v1/missions", app.requireAgent(app.createMissionHandler))
-	router.HandlerFunc(http.MethodGet, "/v1/missions", app.requireAgent(app.listMissionHandler))
-	router.HandlerFunc(http.MethodGet, "/v1/missions/:id", app.requireAgent(app.getMissionHandler))
-	router.HandlerFunc(http.MethodDelete, "/v1/missions/:id", app.requireAgent(app.deleteMissionHandler))
-	router.HandlerFunc(http.MethodPatch, "/v1/missions/:id/complete", app.requireAgent(app.completeMissionHandler))
-	router.HandlerFunc(http.MethodPatch, "/v1/missions/:id/spy-cat/:spy-cat-id", app.requireAgent(app.assignMissionHandler))
-	router.HandlerFunc(http.MethodPost, "/v1/missions/:id/targets", app.requireAgent(app.createMissionTargetHandler))
+	router.HandlerFunc(http.MethodGet, "/v1/healthcheck", app.healthcheckHandler)
+	router.HandlerFunc(http.MethodGet, "/v1/readyz", app.readinessHandler)
+
+	router.HandlerFunc(http.MethodGet, "/v1/breeds", app.listBreedsHandler)
+	router.HandlerFunc(http.MethodGet, "/v1/breeds/:id", app.getBreedHandler)
+
+	router.HandlerFunc(http.MethodGet, "/v1/spy-cats", app.requirePermission(model.PermissionSpyCatsRead, app.listSpyCatHandler))
+	router.HandlerFunc(http.MethodPost, "/v1/spy-cats", app.requirePermission(model.PermissionSpyCatsWrite, app.createSpyCatHandler))
+	router.HandlerFunc(http.MethodGet, "/v1/spy-cats/:id", app.requirePermission(model.PermissionSpyCatsRead, app.getSpyCatHandler))
+	router.HandlerFunc(http.MethodDelete, "/v1/spy-cats/:id", app.requirePermission(model.PermissionSpyCatsWrite, app.deleteSpyCatHandler))
+	router.HandlerFunc(http.MethodPatch, "/v1/spy-cats/:id", app.requirePermission(model.PermissionSpyCatsWrite, app.updateSpyCatHandler))
+	router.HandlerFunc(http.MethodPost, "/v1/spy-cats/:id/restore", app.requirePermission(model.PermissionSpyCatsWrite, app.restoreSpyCatHandler))
+	router.HandlerFunc(http.MethodPost, "/v1/spy-cats/:id/password-reset", app.requirePermission(model.PermissionSpyCatsWrite, app.createSpyCatPasswordResetTokenHandler))
+	router.HandlerFunc(http.MethodDelete, "/v1/spy-cats/:id/tokens", app.requirePermission(model.PermissionSpyCatsWrite, app.revokeSpyCatTokensHandler))
+	router.HandlerFunc(http.MethodGet, "/v1/spy-cats/:id/salary-history", app.requirePermission(model.PermissionSpyCatsRead, app.showSpyCatSalaryHistoryHandler))
+
+	router.HandlerFunc(http.MethodGet, "/v1/reports/payroll", app.requirePermission(model.PermissionSpyCatsRead, app.showPayrollReportHandler))
+
+	router.HandlerFunc(http.MethodPost, "/v1/missions", app.requirePermission(model.PermissionMissionsWrite, app.createMissionHandler))
+	router.HandlerFunc(http.MethodGet, "/v1/missions", app.requirePermission(model.PermissionMissionsRead, app.listMissionHandler))
+	router.HandlerFunc(http.MethodGet, "/v1/missions/:id", app.requirePermission(model.PermissionMissionsRead, app.getMissionHandler))
+	router.HandlerFunc(http.MethodDelete, "/v1/missions/:id", app.requirePermission(model.PermissionMissionsWrite, app.deleteMissionHandler))
+	router.HandlerFunc(http.MethodPatch, "/v1/missions/:id/complete", app.requirePermission(model.PermissionMissionsWrite, app.completeMissionHandler))
+	router.HandlerFunc(http.MethodPatch, "/v1/missions/:id/abort", app.requirePermission(model.PermissionMissionsWrite, app.abortMissionHandler))
+	router.HandlerFunc(http.MethodPatch, "/v1/missions/:id/fail", app.requirePermission(model.PermissionMissionsWrite, app.failMissionHandler))
+	router.HandlerFunc(http.MethodPatch, "/v1/missions/:id/reopen", app.requirePermission(model.PermissionMissionsWrite, app.reopenMissionHandler))
+	router.HandlerFunc(http.MethodPatch, "/v1/missions/:id/spy-cat/:spy-cat-id", app.requirePermission(model.PermissionMissionsWrite, app.assignMissionHandler))
+	router.HandlerFunc(http.MethodDelete, "/v1/missions/:id/spy-cat", app.requirePermission(model.PermissionMissionsWrite, app.unassignMissionHandler))
+	router.HandlerFunc(http.MethodPost, "/v1/missions/:id/handoff", app.requirePermission(model.PermissionMissionsWrite, app.handOffMissionHandler))
+	router.HandlerFunc(http.MethodGet, "/v1/missions/:id/handoffs", app.requirePermission(model.PermissionMissionsRead, app.listMissionHandoffsHandler))
+	router.HandlerFunc(http.MethodPost, "/v1/missions/:id/targets", app.requirePermission(model.PermissionMissionsWrite, app.createMissionTargetHandler))
 	router.HandlerFunc(http.MethodPatch, "/v1/missions/:id/targets/:target-id/complete", app.requireSpyCat(app.completeMissionTargetHandler))
 	router.HandlerFunc(http.MethodPatch, "/v1/missions/:id/targets/:target-id", app.requireSpyCat(app.updateMissionTargetHandler))
-	router.HandlerFunc(http.MethodDelete, "/v1/missions/:id/targets/:target-id", app.requireAgent(app.deleteMissionTargetHandler))
+	router.HandlerFunc(http.MethodDelete, "/v1/missions/:id/targets/:target-id", app.requirePermission(model.PermissionMissionsWrite, app.deleteMissionTargetHandler))
+
+	router.HandlerFunc(http.MethodGet, "/v1/me", app.requireSpyCat(app.showMeHandler))
+	router.HandlerFunc(http.MethodGet, "/v1/me/mission", app.requireSpyCat(app.showMyActiveMissionHandler))
+	router.HandlerFunc(http.MethodGet, "/v1/me/missions", app.requireSpyCat(app.listMyMissionsHandler))
+	router.HandlerFunc(http.MethodPut, "/v1/me/password", app.requireAuthenticatedUser(app.rateLimitFunc(app.limiters.auth, app.updateMyPasswordHandler)))
+
+	router.HandlerFunc(http.MethodPost, "/v1/agents", app.rateLimitFunc(app.limiters.auth, app.createAgentHandler))
+	router.HandlerFunc(http.MethodPut, "/v1/agents/:id/role", app.requirePermission(model.PermissionAgentsWrite, app.changeAgentRoleHandler))
+	router.HandlerFunc(http.MethodPost, "/v1/agents/:id/password-reset", app.requirePermission(model.PermissionAgentsWrite, app.createAgentPasswordResetTokenHandler))
+	router.HandlerFunc(http.MethodDelete, "/v1/agents/:id/tokens", app.requirePermission(model.PermissionAgentsWrite, app.revokeAgentTokensHandler))
+
+	router.HandlerFunc(http.MethodGet, "/v1/lockouts", app.requirePermission(model.PermissionLockoutsRead, app.listLockoutsHandler))
+	router.HandlerFunc(http.MethodDelete, "/v1/lockouts", app.requirePermission(model.PermissionLockoutsWrite, app.clearLockoutHandler))
 
-	router.HandlerFunc(http.MethodPost, "/v1/agents", app.createAgentHandler) //let it be public for demo
+	router.HandlerFunc(http.MethodPost, "/v1/tokens/authentication/spy-cats", app.rateLimitFunc(app.limiters.auth, app.createSpyCatAuthenticationTokenHandler))
+	router.HandlerFunc(http.MethodPost, "/v1/tokens/authentication/agents", app.rateLimitFunc(app.limiters.auth, app.createAgentAuthenticationTokenHandler))
+	router.HandlerFunc(http.MethodPost, "/v1/tokens/invitation", app.requirePermission(model.PermissionAgentsWrite, app.createAgentInvitationHandler))
+	router.HandlerFunc(http.MethodDelete, "/v1/tokens/authentication", app.requireAuthenticatedUser(app.revokeAuthenticationTokenHandler))
+	router.HandlerFunc(http.MethodPut, "/v1/tokens/password-reset", app.rateLimitFunc(app.limiters.auth, app.resetPasswordHandler))
 
-	router.HandlerFunc(http.MethodPost, "/v1/tokens/authentication/spy-cats", app.createSpyCatAuthenticationTokenHandler)
-	router.HandlerFunc(http.MethodPost, "/v1/tokens/authentication/agents", app.createAgentAuthenticationTokenHandler)
+	router.HandlerFunc(http.MethodGet, "/metrics", app.metricsHandler)
 
 	router.Handler(http.MethodGet, "/swagger/*filepath", httpSwagger.WrapHandler)
 
-	return app.recoverPanic(
-		app.logRequestResponse(
-			app.authenticate(
-				router,
+	return app.requestID(
+		app.recoverPanic(
+			app.logRequestResponse(
+				app.enableCORS(
+					app.rateLimitByIP(app.limiters.ip,
+						app.authenticate(
+							app.rateLimit(app.limiters.global, router),
+						),
+					),
+				),
 			),
 		),
 	)
@@ -16,9 +16,9 @@ func (app *application) serve() error {
 	srv := &http.Server{
 		Addr:         fmt.Sprintf(":%d", app.config.port),
 		Handler:      app.routes(),
-		IdleTimeout:  time.Minute,
-		ReadTimeout:  5 * time.Second,
-		WriteTimeout: 10 * time.Second,
+		IdleTimeout:  app.config.server.idleTimeout,
+		ReadTimeout:  app.config.server.readTimeout,
+		WriteTimeout: app.config.server.writeTimeout,
 		ErrorLog:     slog.NewLogLogger(app.logger.Handler(), slog.LevelError),
 	}
 
@@ -30,7 +30,14 @@ func (app *application) serve() error {
 		s := <-quit
 		app.logger.Info("caught signal", "signal", s.String())
 
-		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		close(app.shutdown)
+
+		if app.config.server.shutdownDelay > 0 {
+			app.logger.Info("waiting before shutdown", "delay", app.config.server.shutdownDelay)
+			time.Sleep(app.config.server.shutdownDelay)
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), app.config.server.shutdownTimeout)
 		defer cancel()
 
 		err := srv.Shutdown(ctx)
@@ -3,31 +3,67 @@ package main
 import (
 	"errors"
 	"net/http"
+	"time"
 
 	"github.com/m1crogravity/spy-cat-agency/internal/model"
+	"github.com/m1crogravity/spy-cat-agency/internal/service"
 	"github.com/m1crogravity/spy-cat-agency/internal/storage"
 	"github.com/m1crogravity/spy-cat-agency/internal/validator"
 )
 
 // @Summary List all spy cats
-// @Description Get a list of all spy cats
+// @Description Get a paginated list of spy cats, optionally filtered and sorted
 // @Tags spy-cats
 // @Accept json
 // @Produce json
 // @Security BearerAuth
+// @Param page query int false "Page number" default(1)
+// @Param page_size query int false "Page size" default(20)
+// @Param sort query string false "Sort field, prefix with - for descending. Salaries are sorted by currency first" Enums(id, name, years_of_experience, salary, -id, -name, -years_of_experience, -salary)
+// @Param breed query string false "Breed name or TheCatAPI id, case-insensitive"
+// @Param min_experience query int false "Minimum years of experience"
+// @Param max_experience query int false "Maximum years of experience"
+// @Param currency query string false "Currency of min_salary and max_salary" default(USD)
+// @Param min_salary query string false "Minimum salary, a decimal in the currency"
+// @Param max_salary query string false "Maximum salary, a decimal in the currency"
+// @Param status query string false "Archive status" Enums(active, archived, all) default(active)
 // @Success 200 {object} SpyCatsResponseDoc
 // @Failure 401 {object} ErrorResponseDoc
+// @Failure 403 {object} ErrorResponseDoc
+// @Failure 422 {object} ValidationErrorResponseDoc
 // @Failure 500 {object} ErrorResponseDoc
 // @Router /spy-cats [get]
 func (app *application) listSpyCatHandler(w http.ResponseWriter, r *http.Request) {
-	//no paging
-	spyCats, err := app.spyCatsService.GetAll(r.Context())
+	var filter model.SpyCatsFilter
+
+	v := validator.New()
+	qs := r.URL.Query()
+
+	filter.Breed = app.readString(qs, "breed", "")
+	filter.MinExperience = app.readOptionalInt(qs, "min_experience", v)
+	filter.MaxExperience = app.readOptionalInt(qs, "max_experience", v)
+	currency := app.readString(qs, "currency", model.DefaultCurrency)
+	filter.MinSalary = app.readOptionalMoney(qs, "min_salary", currency, v)
+	filter.MaxSalary = app.readOptionalMoney(qs, "max_salary", currency, v)
+	filter.Status = app.readString(qs, "status", model.SpyCatStatusActive)
+
+	filter.Page = app.readInt(qs, "page", 1, v)
+	filter.PageSize = app.readInt(qs, "page_size", 20, v)
+	filter.Sort = app.readString(qs, "sort", "id")
+	filter.SortSafelist = model.SpyCatsSortSafelist
+
+	if model.ValidateSpyCatsFilter(v, filter); !v.Valid() {
+		app.failedValidationResponse(w, r, v.Errors)
+		return
+	}
+
+	spyCats, metadata, err := app.spyCatsService.GetAll(r.Context(), filter)
 	if err != nil {
 		app.serverErrorResponse(w, r, err)
 		return
 	}
 
-	err = app.writeJson(w, http.StatusOK, envelope{"spy-cats": spyCats})
+	err = app.writeJson(w, http.StatusOK, envelope{"spy-cats": spyCats, "metadata": metadata})
 	if err != nil {
 		app.serverErrorResponse(w, r, err)
 	}
@@ -42,6 +78,7 @@ func (app *application) listSpyCatHandler(w http.ResponseWriter, r *http.Request
 // @Param id path int true "Spy Cat ID"
 // @Success 200 {object} SpyCatResponseDoc
 // @Failure 401 {object} ErrorResponseDoc
+// @Failure 403 {object} ErrorResponseDoc
 // @Failure 404 {object} ErrorResponseDoc
 // @Failure 500 {object} ErrorResponseDoc
 // @Router /spy-cats/{id} [get]
@@ -70,7 +107,7 @@ func (app *application) getSpyCatHandler(w http.ResponseWriter, r *http.Request)
 }
 
 // @Summary Create a new spy cat
-// @Description Create a new spy cat with the provided details
+// @Description Create a new spy cat with the provided details. The salary is annual.
 // @Tags spy-cats
 // @Accept json
 // @Produce json
@@ -79,16 +116,17 @@ func (app *application) getSpyCatHandler(w http.ResponseWriter, r *http.Request)
 // @Success 201 {object} SpyCatResponseDoc
 // @Failure 400 {object} ErrorResponseDoc
 // @Failure 401 {object} ErrorResponseDoc
+// @Failure 403 {object} ErrorResponseDoc
 // @Failure 422 {object} ValidationErrorResponseDoc
 // @Failure 500 {object} ErrorResponseDoc
 // @Router /spy-cats [post]
 func (app *application) createSpyCatHandler(w http.ResponseWriter, r *http.Request) {
 	var input struct {
-		Name              string  `json:"name"`
-		YearsOfExperience int     `json:"years_of_experience"`
-		Breed             string  `json:"breed"`
-		Salary            float64 `json:"salary"`
-		Password          string  `json:"password"`
+		Name              string      `json:"name"`
+		YearsOfExperience int         `json:"years_of_experience"`
+		Breed             string      `json:"breed"`
+		Salary            model.Money `json:"salary"`
+		Password          string      `json:"password"`
 	}
 
 	err := app.readJSON(w, r, &input)
@@ -116,8 +154,13 @@ func (app *application) createSpyCatHandler(w http.ResponseWriter, r *http.Reque
 		return
 	}
 
+	if breed, ok := model.FindBreed(breeds, spyCat.Breed); ok {
+		spyCat.Breed = breed.Name
+	}
+
 	v := validator.New()
-	if model.ValidateSpyCat(v, spyCat, breeds); !v.Valid() {
+	model.ValidateSpyCat(v, spyCat)
+	if model.ValidateBreed(v, spyCat.Breed, breeds); !v.Valid() {
 		app.failedValidationResponse(w, r, v.Errors)
 		return
 	}
@@ -141,7 +184,7 @@ func (app *application) createSpyCatHandler(w http.ResponseWriter, r *http.Reque
 }
 
 // @Summary Delete a spy cat
-// @Description Delete a spy cat by ID
+// @Description Archive a spy cat by ID. Archived spy cats are hidden from lists and can't log in, but stay in the mission history. A spy cat with a mission in progress can't be deleted.
 // @Tags spy-cats
 // @Accept json
 // @Produce json
@@ -149,7 +192,9 @@ func (app *application) createSpyCatHandler(w http.ResponseWriter, r *http.Reque
 // @Param id path int true "Spy Cat ID"
 // @Success 200 {object} MessageResponseDoc
 // @Failure 401 {object} ErrorResponseDoc
+// @Failure 403 {object} ErrorResponseDoc
 // @Failure 404 {object} ErrorResponseDoc
+// @Failure 409 {object} ErrorResponseDoc
 // @Failure 500 {object} ErrorResponseDoc
 // @Router /spy-cats/{id} [delete]
 func (app *application) deleteSpyCatHandler(w http.ResponseWriter, r *http.Request) {
@@ -164,29 +209,78 @@ func (app *application) deleteSpyCatHandler(w http.ResponseWriter, r *http.Reque
 		switch {
 		case errors.Is(err, storage.ErrorModelNotFound):
 			app.notFoundResponse(w, r)
+		case errors.Is(err, service.ErrCantDeleteSpyCat):
+			app.conflictResponse(w, r, err)
 		default:
 			app.serverErrorResponse(w, r, err)
 		}
 		return
 	}
+
+	err = app.tokensService.RevokeAllForUser(r.Context(), id, model.SpyCatUserType)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+		return
+	}
+
 	err = app.writeJson(w, http.StatusOK, envelope{"message": "spy cat successfully deleted"})
 	if err != nil {
 		app.serverErrorResponse(w, r, err)
 	}
 }
 
-// @Summary Update spy cat salary
-// @Description Update the salary of a specific spy cat
+// @Summary Restore a spy cat
+// @Description Restore an archived spy cat by ID
 // @Tags spy-cats
 // @Accept json
 // @Produce json
 // @Security BearerAuth
 // @Param id path int true "Spy Cat ID"
-// @Param salary body UpdateSpyCatSalaryRequestDoc true "Salary Update"
+// @Success 200 {object} SpyCatResponseDoc
+// @Failure 401 {object} ErrorResponseDoc
+// @Failure 403 {object} ErrorResponseDoc
+// @Failure 404 {object} ErrorResponseDoc
+// @Failure 500 {object} ErrorResponseDoc
+// @Router /spy-cats/{id}/restore [post]
+func (app *application) restoreSpyCatHandler(w http.ResponseWriter, r *http.Request) {
+	id, err := app.readIDParam(r, "id")
+	if err != nil {
+		app.notFoundResponse(w, r)
+		return
+	}
+
+	spyCat, err := app.spyCatsService.Restore(r.Context(), id)
+	if err != nil {
+		switch {
+		case errors.Is(err, storage.ErrorModelNotFound):
+			app.notFoundResponse(w, r)
+		default:
+			app.serverErrorResponse(w, r, err)
+		}
+		return
+	}
+
+	err = app.writeJson(w, http.StatusOK, envelope{"spy-cat": spyCat})
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+	}
+}
+
+// @Summary Update a spy cat
+// @Description Update the profile of a specific spy cat with JSON Merge Patch (RFC 7396), fields absent from the body are left untouched. No field can be removed, so a null value is rejected with 422. A salary change is recorded in the salary history, it must not be effective before the latest recorded change.
+// @Tags spy-cats
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Spy Cat ID"
+// @Param spy-cat body UpdateSpyCatRequestDoc true "Spy Cat Update"
 // @Success 200 {object} SpyCatResponseDoc
 // @Failure 400 {object} ErrorResponseDoc
 // @Failure 401 {object} ErrorResponseDoc
+// @Failure 403 {object} ErrorResponseDoc
 // @Failure 404 {object} ErrorResponseDoc
+// @Failure 409 {object} ErrorResponseDoc
+// @Failure 422 {object} ValidationErrorResponseDoc
 // @Failure 500 {object} ErrorResponseDoc
 // @Router /spy-cats/{id} [patch]
 func (app *application) updateSpyCatHandler(w http.ResponseWriter, r *http.Request) {
@@ -196,19 +290,113 @@ func (app *application) updateSpyCatHandler(w http.ResponseWriter, r *http.Reque
 		return
 	}
 
+	spyCat, err := app.spyCatsService.GetById(r.Context(), id)
+	if err != nil {
+		switch {
+		case errors.Is(err, storage.ErrorModelNotFound):
+			app.notFoundResponse(w, r)
+		default:
+			app.serverErrorResponse(w, r, err)
+		}
+		return
+	}
+
 	var input struct {
-		Salary float64 `json:"salary"`
+		Name                *string      `json:"name"`
+		YearsOfExperience   *int         `json:"years_of_experience"`
+		Breed               *string      `json:"breed"`
+		Salary              *model.Money `json:"salary"`
+		SalaryReason        *string      `json:"salary_reason"`
+		SalaryEffectiveDate *string      `json:"salary_effective_date"`
 	}
 
-	err = app.readJSON(w, r, &input)
+	nulls, err := app.readMergePatch(w, r, &input)
 	if err != nil {
 		app.badRequestResponse(w, r, err)
 		return
 	}
 
-	spyCat, err := app.spyCatsService.UpdateSalary(r.Context(), id, input.Salary)
+	v := validator.New()
+
+	// None of the fields can be removed, so a merge patch null is rejected
+	// instead of being taken for an absent field.
+	for _, key := range nulls {
+		v.AddError(key, "must not be null")
+	}
+	if !v.Valid() {
+		app.failedValidationResponse(w, r, v.Errors)
+		return
+	}
+
+	var change *model.SalaryChange
+	if input.Salary != nil && *input.Salary != spyCat.Salary {
+		change = &model.SalaryChange{
+			SpyCatId:      spyCat.Id,
+			AgentId:       app.contextGetAgent(r).Id,
+			OldSalary:     spyCat.Salary,
+			NewSalary:     *input.Salary,
+			EffectiveDate: time.Now().UTC().Truncate(24 * time.Hour),
+		}
+		if input.SalaryReason != nil {
+			change.Reason = *input.SalaryReason
+		}
+		if input.SalaryEffectiveDate != nil {
+			change.EffectiveDate, err = time.Parse(time.DateOnly, *input.SalaryEffectiveDate)
+			if err != nil {
+				v.AddError("salary_effective_date", "must be a date in YYYY-MM-DD format")
+			}
+		}
+		model.ValidateSalaryChange(v, change)
+	} else {
+		v.Check(input.SalaryReason == nil, "salary_reason", "must only be given with a salary change")
+		v.Check(input.SalaryEffectiveDate == nil, "salary_effective_date", "must only be given with a salary change")
+	}
+
+	if input.Name != nil {
+		spyCat.Name = *input.Name
+	}
+	if input.YearsOfExperience != nil {
+		spyCat.YearsOfExperience = *input.YearsOfExperience
+	}
+	if input.Salary != nil {
+		spyCat.Salary = *input.Salary
+	}
+
+	// The stored breed was resolved when it was written, so the breeds are
+	// only needed when the patch changes it.
+	if input.Breed != nil {
+		breeds, err := app.spyCatsService.GetBreeds(r.Context())
+		if err != nil {
+			app.serverErrorResponse(w, r, err)
+			return
+		}
+
+		spyCat.Breed = *input.Breed
+		if breed, ok := model.FindBreed(breeds, spyCat.Breed); ok {
+			spyCat.Breed = breed.Name
+		}
+		model.ValidateBreed(v, spyCat.Breed, breeds)
+	}
+
+	if model.ValidateSpyCat(v, spyCat); !v.Valid() {
+		app.failedValidationResponse(w, r, v.Errors)
+		return
+	}
+
+	err = app.spyCatsService.Update(r.Context(), spyCat, change)
 	if err != nil {
-		app.serverErrorResponse(w, r, err)
+		switch {
+		case errors.Is(err, storage.ErrorUniqueConstraintViolation):
+			v.AddError("name", "a spy cat with this name is already exists")
+			app.failedValidationResponse(w, r, v.Errors)
+		case errors.Is(err, model.ErrBackdatedSalaryChange):
+			v.AddError("salary_effective_date", "must not be before the latest salary change")
+			app.failedValidationResponse(w, r, v.Errors)
+		case errors.Is(err, storage.ErrorEditConflict):
+			app.editConflictResponse(w, r)
+		default:
+			app.serverErrorResponse(w, r, err)
+		}
 		return
 	}
 
@@ -217,3 +405,121 @@ func (app *application) updateSpyCatHandler(w http.ResponseWriter, r *http.Reque
 		app.serverErrorResponse(w, r, err)
 	}
 }
+
+// @Summary Revoke spy cat tokens
+// @Description Revoke all tokens issued to a specific spy cat
+// @Tags spy-cats
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Spy Cat ID"
+// @Success 200 {object} MessageResponseDoc
+// @Failure 401 {object} ErrorResponseDoc
+// @Failure 403 {object} ErrorResponseDoc
+// @Failure 404 {object} ErrorResponseDoc
+// @Failure 500 {object} ErrorResponseDoc
+// @Router /spy-cats/{id}/tokens [delete]
+func (app *application) revokeSpyCatTokensHandler(w http.ResponseWriter, r *http.Request) {
+	id, err := app.readIDParam(r, "id")
+	if err != nil {
+		app.notFoundResponse(w, r)
+		return
+	}
+
+	spyCat, err := app.spyCatsService.GetById(r.Context(), id)
+	if err != nil {
+		switch {
+		case errors.Is(err, storage.ErrorModelNotFound):
+			app.notFoundResponse(w, r)
+		default:
+			app.serverErrorResponse(w, r, err)
+		}
+		return
+	}
+
+	err = app.tokensService.RevokeAllForUser(r.Context(), spyCat.Id, model.SpyCatUserType)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+		return
+	}
+
+	err = app.writeJson(w, http.StatusOK, envelope{"message": "spy cat tokens successfully revoked"})
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+	}
+}
+
+// @Summary Get spy cat salary history
+// @Description Get all salary changes of a specific spy cat ordered by effective date
+// @Tags spy-cats
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Spy Cat ID"
+// @Success 200 {object} SalaryHistoryResponseDoc
+// @Failure 401 {object} ErrorResponseDoc
+// @Failure 403 {object} ErrorResponseDoc
+// @Failure 404 {object} ErrorResponseDoc
+// @Failure 500 {object} ErrorResponseDoc
+// @Router /spy-cats/{id}/salary-history [get]
+func (app *application) showSpyCatSalaryHistoryHandler(w http.ResponseWriter, r *http.Request) {
+	id, err := app.readIDParam(r, "id")
+	if err != nil {
+		app.notFoundResponse(w, r)
+		return
+	}
+
+	changes, err := app.spyCatsService.GetSalaryHistory(r.Context(), id)
+	if err != nil {
+		switch {
+		case errors.Is(err, storage.ErrorModelNotFound):
+			app.notFoundResponse(w, r)
+		default:
+			app.serverErrorResponse(w, r, err)
+		}
+		return
+	}
+
+	err = app.writeJson(w, http.StatusOK, envelope{"salary_history": changes})
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+	}
+}
+
+// @Summary Get payroll report
+// @Description Get the monthly salary cost per breed and experience band for a range of months. Salaries are annual, so a month costs a twelfth of the salary in effect on its first day. Spy cats are charged for every month they were on the books in, from the month they were created in to the month they were archived in, grouped by their current breed and experience band.
+// @Tags spy-cats
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param from query string true "First month (YYYY-MM)"
+// @Param to query string true "Last month (YYYY-MM)"
+// @Success 200 {object} PayrollResponseDoc
+// @Failure 401 {object} ErrorResponseDoc
+// @Failure 403 {object} ErrorResponseDoc
+// @Failure 422 {object} ValidationErrorResponseDoc
+// @Failure 500 {object} ErrorResponseDoc
+// @Router /reports/payroll [get]
+func (app *application) showPayrollReportHandler(w http.ResponseWriter, r *http.Request) {
+	qs := r.URL.Query()
+	v := validator.New()
+
+	from := app.readMonth(qs, "from", v)
+	to := app.readMonth(qs, "to", v)
+
+	if model.ValidatePayrollRange(v, from, to); !v.Valid() {
+		app.failedValidationResponse(w, r, v.Errors)
+		return
+	}
+
+	report, err := app.spyCatsService.GetPayrollReport(r.Context(), from, to)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+		return
+	}
+
+	err = app.writeJson(w, http.StatusOK, envelope{"payroll": report})
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+	}
+}
@@ -0,0 +1,62 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/julienschmidt/httprouter"
+	"github.com/m1crogravity/spy-cat-agency/internal/model"
+	"github.com/m1crogravity/spy-cat-agency/internal/service"
+	"github.com/m1crogravity/spy-cat-agency/internal/storage/memory"
+)
+
+// unavailableBreeds is a breeds source that is down.
+type unavailableBreeds struct{}
+
+func (unavailableBreeds) FindAll(context.Context) ([]model.Breed, error) {
+	return nil, errors.New("breeds API unavailable")
+}
+
+func TestUpdateSpyCatHandlerWithoutBreeds(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	repo := memory.NewSpyCatRepository()
+	app := &application{
+		logger:         logger,
+		spyCatsService: service.NewSpyCatService(repo, unavailableBreeds{}, logger),
+	}
+
+	spyCat := &model.SpyCat{Name: "Tom", Breed: "Russian Blue", Salary: model.Money{Amount: 100, Currency: "USD"}}
+	err := spyCat.Password.Set("pa55word")
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = repo.Create(t.Context(), spyCat)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tc := []struct {
+		body     string
+		expected int
+	}{
+		{`{"name":"Thomas"}`, http.StatusOK},
+		{`{"breed":"Siamese"}`, http.StatusInternalServerError},
+	}
+
+	for _, tt := range tc {
+		r := httptest.NewRequest(http.MethodPatch, "/v1/spy-cats/1", strings.NewReader(tt.body))
+		r = r.WithContext(context.WithValue(r.Context(), httprouter.ParamsKey, httprouter.Params{{Key: "id", Value: "1"}}))
+		rr := httptest.NewRecorder()
+		app.updateSpyCatHandler(rr, r)
+
+		if rr.Code != tt.expected {
+			t.Fatalf("%s: expected status %d, got %d: %s", tt.body, tt.expected, rr.Code, rr.Body)
+		}
+	}
+}
@@ -3,7 +3,6 @@ package main
 import (
 	"errors"
 	"net/http"
-	"time"
 
 	"github.com/m1crogravity/spy-cat-agency/internal/model"
 	"github.com/m1crogravity/spy-cat-agency/internal/storage"
@@ -20,6 +19,7 @@ import (
 // @Failure 400 {object} ErrorResponseDoc
 // @Failure 401 {object} ErrorResponseDoc
 // @Failure 422 {object} ValidationErrorResponseDoc
+// @Failure 429 {object} ErrorResponseDoc
 // @Failure 500 {object} ErrorResponseDoc
 // @Router /tokens/authentication/spy-cats [post]
 func (app *application) createSpyCatAuthenticationTokenHandler(w http.ResponseWriter, r *http.Request) {
@@ -43,10 +43,24 @@ func (app *application) createSpyCatAuthenticationTokenHandler(w http.ResponseWr
 		return
 	}
 
+	// The attempt counts as a failure until the password is proven right.
+	wait, err := app.lockoutsService.Attempt(r.Context(), model.SpyCatUserType, input.Name)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+		return
+	}
+
+	if wait > 0 {
+		app.accountLockedResponse(w, r, wait)
+		return
+	}
+
 	spyCat, err := app.spyCatsService.GetByName(r.Context(), input.Name)
 	if err != nil {
 		switch {
 		case errors.Is(err, storage.ErrorModelNotFound):
+			// Keep the response time of unknown names equal to a wrong password.
+			_, _ = model.DummyPassword.Matches(input.Password)
 			app.invalidCredentialsResponse(w, r)
 		default:
 			app.serverErrorResponse(w, r, err)
@@ -65,7 +79,13 @@ func (app *application) createSpyCatAuthenticationTokenHandler(w http.ResponseWr
 		return
 	}
 
-	token, err := app.tokensService.Create(r.Context(), spyCat.Id, model.SpyCatUserType, 24*time.Hour, model.ScopeAuthentication)
+	err = app.lockoutsService.RegisterSuccess(r.Context(), model.SpyCatUserType, input.Name)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+		return
+	}
+
+	token, err := app.tokensService.Create(r.Context(), spyCat.Id, model.SpyCatUserType, app.config.tokens.authenticationTtl, model.ScopeAuthentication)
 	if err != nil {
 		app.serverErrorResponse(w, r, err)
 		return
@@ -88,6 +108,7 @@ func (app *application) createSpyCatAuthenticationTokenHandler(w http.ResponseWr
 // @Failure 400 {object} ErrorResponseDoc
 // @Failure 401 {object} ErrorResponseDoc
 // @Failure 422 {object} ValidationErrorResponseDoc
+// @Failure 429 {object} ErrorResponseDoc
 // @Failure 500 {object} ErrorResponseDoc
 // @Router /tokens/authentication/agents [post]
 func (app *application) createAgentAuthenticationTokenHandler(w http.ResponseWriter, r *http.Request) {
@@ -111,10 +132,24 @@ func (app *application) createAgentAuthenticationTokenHandler(w http.ResponseWri
 		return
 	}
 
+	// The attempt counts as a failure until the password is proven right.
+	wait, err := app.lockoutsService.Attempt(r.Context(), model.AgentUserType, input.Name)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+		return
+	}
+
+	if wait > 0 {
+		app.accountLockedResponse(w, r, wait)
+		return
+	}
+
 	agent, err := app.agentsService.GetByName(r.Context(), input.Name)
 	if err != nil {
 		switch {
 		case errors.Is(err, storage.ErrorModelNotFound):
+			// Keep the response time of unknown names equal to a wrong password.
+			_, _ = model.DummyPassword.Matches(input.Password)
 			app.invalidCredentialsResponse(w, r)
 		default:
 			app.serverErrorResponse(w, r, err)
@@ -133,7 +168,13 @@ func (app *application) createAgentAuthenticationTokenHandler(w http.ResponseWri
 		return
 	}
 
-	token, err := app.tokensService.Create(r.Context(), agent.Id, model.AgentUserType, 24*time.Hour, model.ScopeAuthentication)
+	err = app.lockoutsService.RegisterSuccess(r.Context(), model.AgentUserType, input.Name)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+		return
+	}
+
+	token, err := app.tokensService.Create(r.Context(), agent.Id, model.AgentUserType, app.config.tokens.authenticationTtl, model.ScopeAuthentication)
 	if err != nil {
 		app.serverErrorResponse(w, r, err)
 		return
@@ -145,3 +186,28 @@ func (app *application) createAgentAuthenticationTokenHandler(w http.ResponseWri
 		return
 	}
 }
+
+// @Summary Revoke current authentication token
+// @Description Log out by revoking the authentication token used for this request
+// @Tags authentication
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} MessageResponseDoc
+// @Failure 401 {object} ErrorResponseDoc
+// @Failure 500 {object} ErrorResponseDoc
+// @Router /tokens/authentication [delete]
+func (app *application) revokeAuthenticationTokenHandler(w http.ResponseWriter, r *http.Request) {
+	token := app.contextGetToken(r)
+
+	err := app.tokensService.Revoke(r.Context(), token)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+		return
+	}
+
+	err = app.writeJson(w, http.StatusOK, envelope{"message": "authentication token successfully revoked"})
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+	}
+}
@@ -0,0 +1,91 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// purgeExpired periodically deletes expired tokens and stale lockouts.
+func (app *application) purgeExpired(interval time.Duration, batchSize int) {
+	if interval <= 0 {
+		app.logger.Info("expired tokens and stale lockouts purge is disabled")
+		return
+	}
+
+	app.wg.Add(1)
+
+	go func() {
+		defer app.wg.Done()
+
+		defer func() {
+			if err := recover(); err != nil {
+				app.logger.Error(fmt.Sprintf("%v", err))
+			}
+		}()
+
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-app.shutdown:
+				return
+			case <-ticker.C:
+				ctx, cancel := context.WithTimeout(context.Background(), interval)
+				deleted, err := app.tokensService.PurgeExpired(ctx, batchSize)
+				if err != nil {
+					app.logger.Error(err.Error(), "purged", deleted)
+				} else {
+					app.logger.Info("purged expired tokens", "count", deleted)
+				}
+
+				deleted, err = app.lockoutsService.PurgeStale(ctx, batchSize)
+				cancel()
+				if err != nil {
+					app.logger.Error(err.Error(), "purged", deleted)
+					continue
+				}
+
+				app.logger.Info("purged stale lockouts", "count", deleted)
+			}
+		}
+	}()
+}
+
+func (app *application) evictRateLimiterBuckets(idleTimeout time.Duration) {
+	limiters := app.limiters.all()
+	if len(limiters) == 0 || idleTimeout <= 0 {
+		return
+	}
+
+	app.wg.Add(1)
+
+	go func() {
+		defer app.wg.Done()
+
+		defer func() {
+			if err := recover(); err != nil {
+				app.logger.Error(fmt.Sprintf("%v", err))
+			}
+		}()
+
+		ticker := time.NewTicker(time.Minute)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-app.shutdown:
+				return
+			case now := <-ticker.C:
+				evicted := 0
+				for _, limiter := range limiters {
+					evicted += limiter.evict(now.Add(-idleTimeout))
+				}
+				if evicted > 0 {
+					app.logger.Debug("evicted rate limiter buckets", "count", evicted)
+				}
+			}
+		}
+	}()
+}
@@ -26,7 +26,7 @@ const docTemplate = `{
     "paths": {
         "/agents": {
             "post": {
-                "description": "Create a new agent with name and password",
+                "description": "Redeem an invitation token and create a new agent with name and password. Invited agents start with the analyst role.",
                 "consumes": [
                     "application/json"
                 ],
@@ -67,6 +67,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/main.ValidationErrorResponseDoc"
                         }
                     },
+                    "429": {
+                        "description": "Too Many Requests",
+                        "schema": {
+                            "$ref": "#/definitions/main.ErrorResponseDoc"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -76,14 +82,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/missions": {
-            "get": {
+        "/agents/{id}/password-reset": {
+            "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Get a list of all missions",
+                "description": "Issue a single-use token which allows the agent to set a new password",
                 "consumes": [
                     "application/json"
                 ],
@@ -91,14 +97,23 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "missions"
+                    "agents"
+                ],
+                "summary": "Create agent password reset token",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Agent ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
                 ],
-                "summary": "List all missions",
                 "responses": {
-                    "200": {
-                        "description": "OK",
+                    "201": {
+                        "description": "Created",
                         "schema": {
-                            "$ref": "#/definitions/main.MissionsResponseDoc"
+                            "$ref": "#/definitions/main.PasswordResetTokenResponseDoc"
                         }
                     },
                     "401": {
@@ -107,6 +122,18 @@ const docTemplate = `{
                             "$ref": "#/definitions/main.ErrorResponseDoc"
                         }
                     },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/main.ErrorResponseDoc"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/main.ErrorResponseDoc"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -114,14 +141,16 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "post": {
+            }
+        },
+        "/agents/{id}/role": {
+            "put": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Create a new mission with targets",
+                "description": "Change the role, and so the permissions, of a specific agent",
                 "consumes": [
                     "application/json"
                 ],
@@ -129,25 +158,32 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "missions"
+                    "agents"
                 ],
-                "summary": "Create a new mission",
+                "summary": "Change agent role",
                 "parameters": [
                     {
-                        "description": "Mission Details",
-                        "name": "mission",
+                        "type": "integer",
+                        "description": "Agent ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Agent Role",
+                        "name": "role",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/main.CreateMissionRequestDoc"
+                            "$ref": "#/definitions/main.ChangeAgentRoleRequestDoc"
                         }
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Created",
+                    "200": {
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/main.MissionResponseDoc"
+                            "$ref": "#/definitions/main.AgentResponseDoc"
                         }
                     },
                     "400": {
@@ -162,6 +198,18 @@ const docTemplate = `{
                             "$ref": "#/definitions/main.ErrorResponseDoc"
                         }
                     },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/main.ErrorResponseDoc"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/main.ErrorResponseDoc"
+                        }
+                    },
                     "422": {
                         "description": "Unprocessable Entity",
                         "schema": {
@@ -177,14 +225,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/missions/{id}": {
-            "get": {
+        "/agents/{id}/tokens": {
+            "delete": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Get details of a specific mission by ID",
+                "description": "Revoke all tokens issued to a specific agent",
                 "consumes": [
                     "application/json"
                 ],
@@ -192,13 +240,13 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "missions"
+                    "agents"
                 ],
-                "summary": "Get a mission by ID",
+                "summary": "Revoke agent tokens",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Mission ID",
+                        "description": "Agent ID",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -208,7 +256,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/main.MissionResponseDoc"
+                            "$ref": "#/definitions/main.MessageResponseDoc"
                         }
                     },
                     "401": {
@@ -217,6 +265,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/main.ErrorResponseDoc"
                         }
                     },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/main.ErrorResponseDoc"
+                        }
+                    },
                     "404": {
                         "description": "Not Found",
                         "schema": {
@@ -230,14 +284,48 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "delete": {
-                "security": [
+            }
+        },
+        "/breeds": {
+            "get": {
+                "description": "Get the breeds a spy cat can be of, optionally filtered by a case-insensitive name prefix",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "breeds"
+                ],
+                "summary": "List breeds",
+                "parameters": [
                     {
-                        "BearerAuth": []
+                        "type": "string",
+                        "description": "Breed name prefix",
+                        "name": "name",
+                        "in": "query"
                     }
                 ],
-                "description": "Delete a mission by ID",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/main.BreedsResponseDoc"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/main.ErrorResponseDoc"
+                        }
+                    }
+                }
+            }
+        },
+        "/breeds/{id}": {
+            "get": {
+                "description": "Get a breed by its TheCatAPI ID",
                 "consumes": [
                     "application/json"
                 ],
@@ -245,13 +333,13 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "missions"
+                    "breeds"
                 ],
-                "summary": "Delete a mission",
+                "summary": "Get breed by ID",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "Mission ID",
+                        "type": "string",
+                        "description": "Breed ID",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -261,20 +349,40 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/main.MessageResponseDoc"
+                            "$ref": "#/definitions/main.BreedResponseDoc"
                         }
                     },
-                    "401": {
-                        "description": "Unauthorized",
+                    "404": {
+                        "description": "Not Found",
                         "schema": {
                             "$ref": "#/definitions/main.ErrorResponseDoc"
                         }
                     },
-                    "404": {
-                        "description": "Not Found",
+                    "500": {
+                        "description": "Internal Server Error",
                         "schema": {
                             "$ref": "#/definitions/main.ErrorResponseDoc"
                         }
+                    }
+                }
+            }
+        },
+        "/healthcheck": {
+            "get": {
+                "description": "Report that the process is up, with its version and environment",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "health"
+                ],
+                "summary": "Liveness check",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/main.HealthcheckResponseDoc"
+                        }
                     },
                     "500": {
                         "description": "Internal Server Error",
@@ -285,14 +393,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/missions/{id}/complete": {
-            "patch": {
+        "/lockouts": {
+            "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Mark a mission as completed",
+                "description": "Get all names which are currently locked out after failed logins",
                 "consumes": [
                     "application/json"
                 ],
@@ -300,23 +408,14 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "missions"
-                ],
-                "summary": "Complete a mission",
-                "parameters": [
-                    {
-                        "type": "integer",
-                        "description": "Mission ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    }
+                    "lockouts"
                 ],
+                "summary": "List login lockouts",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/main.MissionResponseDoc"
+                            "$ref": "#/definitions/main.LockoutsResponseDoc"
                         }
                     },
                     "401": {
@@ -325,8 +424,8 @@ const docTemplate = `{
                             "$ref": "#/definitions/main.ErrorResponseDoc"
                         }
                     },
-                    "404": {
-                        "description": "Not Found",
+                    "403": {
+                        "description": "Forbidden",
                         "schema": {
                             "$ref": "#/definitions/main.ErrorResponseDoc"
                         }
@@ -338,16 +437,14 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/missions/{id}/spy-cat/{spy-cat-id}": {
-            "patch": {
+            },
+            "delete": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Assign a mission to a specific spy cat",
+                "description": "Forget failed logins of a name, which immediately lifts its lockout",
                 "consumes": [
                     "application/json"
                 ],
@@ -355,22 +452,22 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "missions"
+                    "lockouts"
                 ],
-                "summary": "Assign mission to spy cat",
+                "summary": "Clear login lockout",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "Mission ID",
-                        "name": "id",
-                        "in": "path",
+                        "type": "string",
+                        "description": "User type (spy-cat, agent)",
+                        "name": "user_type",
+                        "in": "query",
                         "required": true
                     },
                     {
-                        "type": "integer",
-                        "description": "Spy Cat ID",
-                        "name": "spy-cat-id",
-                        "in": "path",
+                        "type": "string",
+                        "description": "Login name",
+                        "name": "name",
+                        "in": "query",
                         "required": true
                     }
                 ],
@@ -378,7 +475,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/main.MissionResponseDoc"
+                            "$ref": "#/definitions/main.MessageResponseDoc"
                         }
                     },
                     "401": {
@@ -387,12 +484,24 @@ const docTemplate = `{
                             "$ref": "#/definitions/main.ErrorResponseDoc"
                         }
                     },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/main.ErrorResponseDoc"
+                        }
+                    },
                     "404": {
                         "description": "Not Found",
                         "schema": {
                             "$ref": "#/definitions/main.ErrorResponseDoc"
                         }
                     },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/main.ValidationErrorResponseDoc"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -402,14 +511,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/missions/{id}/targets": {
-            "post": {
+        "/me": {
+            "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Add a new target to a mission",
+                "description": "Get the profile of the authenticated spy cat",
                 "consumes": [
                     "application/json"
                 ],
@@ -417,38 +526,14 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "missions"
-                ],
-                "summary": "Create mission target",
-                "parameters": [
-                    {
-                        "type": "integer",
-                        "description": "Mission ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "description": "Target Details",
-                        "name": "target",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/main.CreateTargetRequestDoc"
-                        }
-                    }
+                    "me"
                 ],
+                "summary": "Get current spy cat",
                 "responses": {
-                    "201": {
-                        "description": "Created",
-                        "schema": {
-                            "$ref": "#/definitions/main.TargetResponseDoc"
-                        }
-                    },
-                    "400": {
-                        "description": "Bad Request",
+                    "200": {
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/main.ErrorResponseDoc"
+                            "$ref": "#/definitions/main.SpyCatResponseDoc"
                         }
                     },
                     "401": {
@@ -457,12 +542,6 @@ const docTemplate = `{
                             "$ref": "#/definitions/main.ErrorResponseDoc"
                         }
                     },
-                    "404": {
-                        "description": "Not Found",
-                        "schema": {
-                            "$ref": "#/definitions/main.ErrorResponseDoc"
-                        }
-                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -472,14 +551,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/missions/{id}/targets/{target-id}": {
-            "delete": {
+        "/me/mission": {
+            "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Delete a mission target by ID",
+                "description": "Get the mission the authenticated spy cat is currently working on",
                 "consumes": [
                     "application/json"
                 ],
@@ -487,30 +566,14 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "missions"
-                ],
-                "summary": "Delete mission target",
-                "parameters": [
-                    {
-                        "type": "integer",
-                        "description": "Mission ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "type": "integer",
-                        "description": "Target ID",
-                        "name": "target-id",
-                        "in": "path",
-                        "required": true
-                    }
+                    "me"
                 ],
+                "summary": "Get current mission",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/main.MessageResponseDoc"
+                            "$ref": "#/definitions/main.MissionResponseDoc"
                         }
                     },
                     "401": {
@@ -532,14 +595,16 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "patch": {
-                "security": [
+            }
+        },
+        "/me/missions": {
+            "get": {
+                "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Update the notes of a mission target",
+                "description": "Get the mission history of the authenticated spy cat ordered by ID. Use the returned next_cursor to fetch the following page.",
                 "consumes": [
                     "application/json"
                 ],
@@ -547,45 +612,42 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "missions"
+                    "me"
                 ],
-                "summary": "Update mission target",
+                "summary": "List own missions",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "Mission ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "enum": [
+                            "created",
+                            "in_progress",
+                            "completed",
+                            "aborted",
+                            "failed"
+                        ],
+                        "type": "string",
+                        "description": "Mission state",
+                        "name": "state",
+                        "in": "query"
                     },
                     {
-                        "type": "integer",
-                        "description": "Target ID",
-                        "name": "target-id",
-                        "in": "path",
-                        "required": true
+                        "type": "string",
+                        "description": "Cursor returned by the previous page",
+                        "name": "cursor",
+                        "in": "query"
                     },
                     {
-                        "description": "Target Notes",
-                        "name": "notes",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/main.UpdateTargetNotesRequestDoc"
-                        }
+                        "type": "integer",
+                        "default": 20,
+                        "description": "Page size",
+                        "name": "limit",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/main.TargetResponseDoc"
-                        }
-                    },
-                    "400": {
-                        "description": "Bad Request",
-                        "schema": {
-                            "$ref": "#/definitions/main.ErrorResponseDoc"
+                            "$ref": "#/definitions/main.MissionsResponseDoc"
                         }
                     },
                     "401": {
@@ -594,10 +656,10 @@ const docTemplate = `{
                             "$ref": "#/definitions/main.ErrorResponseDoc"
                         }
                     },
-                    "404": {
-                        "description": "Not Found",
+                    "422": {
+                        "description": "Unprocessable Entity",
                         "schema": {
-                            "$ref": "#/definitions/main.ErrorResponseDoc"
+                            "$ref": "#/definitions/main.ValidationErrorResponseDoc"
                         }
                     },
                     "500": {
@@ -609,14 +671,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/missions/{id}/targets/{target-id}/complete": {
-            "patch": {
+        "/me/password": {
+            "put": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Mark a mission target as completed",
+                "description": "Change the password of the authenticated spy cat or agent",
                 "consumes": [
                     "application/json"
                 ],
@@ -624,30 +686,31 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "missions"
+                    "me"
                 ],
-                "summary": "Complete mission target",
+                "summary": "Change own password",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "Mission ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "type": "integer",
-                        "description": "Target ID",
-                        "name": "target-id",
-                        "in": "path",
-                        "required": true
+                        "description": "Current and new password",
+                        "name": "password",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/main.ChangePasswordRequestDoc"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/main.TargetResponseDoc"
+                            "$ref": "#/definitions/main.MessageResponseDoc"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/main.ErrorResponseDoc"
                         }
                     },
                     "401": {
@@ -656,8 +719,14 @@ const docTemplate = `{
                             "$ref": "#/definitions/main.ErrorResponseDoc"
                         }
                     },
-                    "404": {
-                        "description": "Not Found",
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/main.ValidationErrorResponseDoc"
+                        }
+                    },
+                    "429": {
+                        "description": "Too Many Requests",
                         "schema": {
                             "$ref": "#/definitions/main.ErrorResponseDoc"
                         }
@@ -671,14 +740,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/spy-cats": {
+        "/missions": {
             "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Get a list of all spy cats",
+                "description": "Get a list of missions ordered by ID, optionally filtered. Use the returned next_cursor to fetch the following page.",
                 "consumes": [
                     "application/json"
                 ],
@@ -686,14 +755,54 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "spy-cats"
+                    "missions"
+                ],
+                "summary": "List all missions",
+                "parameters": [
+                    {
+                        "enum": [
+                            "created",
+                            "in_progress",
+                            "completed",
+                            "aborted",
+                            "failed"
+                        ],
+                        "type": "string",
+                        "description": "Mission state",
+                        "name": "state",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Assigned spy cat ID",
+                        "name": "spy_cat_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Country of at least one of the mission targets",
+                        "name": "country",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cursor returned by the previous page",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 20,
+                        "description": "Page size",
+                        "name": "limit",
+                        "in": "query"
+                    }
                 ],
-                "summary": "List all spy cats",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/main.SpyCatsResponseDoc"
+                            "$ref": "#/definitions/main.MissionsResponseDoc"
                         }
                     },
                     "401": {
@@ -702,6 +811,18 @@ const docTemplate = `{
                             "$ref": "#/definitions/main.ErrorResponseDoc"
                         }
                     },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/main.ErrorResponseDoc"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/main.ValidationErrorResponseDoc"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -716,7 +837,7 @@ const docTemplate = `{
                         "BearerAuth": []
                     }
                 ],
-                "description": "Create a new spy cat with the provided details",
+                "description": "Create a new mission with targets",
                 "consumes": [
                     "application/json"
                 ],
@@ -724,17 +845,17 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "spy-cats"
+                    "missions"
                 ],
-                "summary": "Create a new spy cat",
+                "summary": "Create a new mission",
                 "parameters": [
                     {
-                        "description": "Spy Cat Details",
-                        "name": "spy-cat",
+                        "description": "Mission Details",
+                        "name": "mission",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/main.CreateSpyCatRequestDoc"
+                            "$ref": "#/definitions/main.CreateMissionRequestDoc"
                         }
                     }
                 ],
@@ -742,7 +863,7 @@ const docTemplate = `{
                     "201": {
                         "description": "Created",
                         "schema": {
-                            "$ref": "#/definitions/main.SpyCatResponseDoc"
+                            "$ref": "#/definitions/main.MissionResponseDoc"
                         }
                     },
                     "400": {
@@ -757,6 +878,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/main.ErrorResponseDoc"
                         }
                     },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/main.ErrorResponseDoc"
+                        }
+                    },
                     "422": {
                         "description": "Unprocessable Entity",
                         "schema": {
@@ -772,14 +899,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/spy-cats/{id}": {
+        "/missions/{id}": {
             "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Get details of a specific spy cat by ID",
+                "description": "Get details of a specific mission by ID",
                 "consumes": [
                     "application/json"
                 ],
@@ -787,13 +914,13 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "spy-cats"
+                    "missions"
                 ],
-                "summary": "Get a spy cat by ID",
+                "summary": "Get a mission by ID",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Spy Cat ID",
+                        "description": "Mission ID",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -803,7 +930,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/main.SpyCatResponseDoc"
+                            "$ref": "#/definitions/main.MissionResponseDoc"
                         }
                     },
                     "401": {
@@ -812,6 +939,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/main.ErrorResponseDoc"
                         }
                     },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/main.ErrorResponseDoc"
+                        }
+                    },
                     "404": {
                         "description": "Not Found",
                         "schema": {
@@ -832,7 +965,7 @@ const docTemplate = `{
                         "BearerAuth": []
                     }
                 ],
-                "description": "Delete a spy cat by ID",
+                "description": "Delete a mission by ID",
                 "consumes": [
                     "application/json"
                 ],
@@ -840,13 +973,13 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "spy-cats"
+                    "missions"
                 ],
-                "summary": "Delete a spy cat",
+                "summary": "Delete a mission",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Spy Cat ID",
+                        "description": "Mission ID",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -865,6 +998,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/main.ErrorResponseDoc"
                         }
                     },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/main.ErrorResponseDoc"
+                        }
+                    },
                     "404": {
                         "description": "Not Found",
                         "schema": {
@@ -878,14 +1017,16 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
+            }
+        },
+        "/missions/{id}/abort": {
             "patch": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Update the salary of a specific spy cat",
+                "description": "Call off a created or in progress mission, the reason is required",
                 "consumes": [
                     "application/json"
                 ],
@@ -893,24 +1034,24 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "spy-cats"
+                    "missions"
                 ],
-                "summary": "Update spy cat salary",
+                "summary": "Abort a mission",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Spy Cat ID",
+                        "description": "Mission ID",
                         "name": "id",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "description": "Salary Update",
-                        "name": "salary",
+                        "description": "Abort Reason",
+                        "name": "reason",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/main.UpdateSpyCatSalaryRequestDoc"
+                            "$ref": "#/definitions/main.MissionReasonRequestDoc"
                         }
                     }
                 ],
@@ -918,7 +1059,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/main.SpyCatResponseDoc"
+                            "$ref": "#/definitions/main.MissionResponseDoc"
                         }
                     },
                     "400": {
@@ -933,12 +1074,30 @@ const docTemplate = `{
                             "$ref": "#/definitions/main.ErrorResponseDoc"
                         }
                     },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/main.ErrorResponseDoc"
+                        }
+                    },
                     "404": {
                         "description": "Not Found",
                         "schema": {
                             "$ref": "#/definitions/main.ErrorResponseDoc"
                         }
                     },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/main.ErrorResponseDoc"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/main.ValidationErrorResponseDoc"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -948,9 +1107,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/tokens/authentication/agents": {
-            "post": {
-                "description": "Authenticate an agent and return a JWT token",
+        "/missions/{id}/complete": {
+            "patch": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Mark a mission in progress as completed",
                 "consumes": [
                     "application/json"
                 ],
@@ -958,43 +1122,47 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "authentication"
+                    "missions"
                 ],
-                "summary": "Create agent authentication token",
+                "summary": "Complete a mission",
                 "parameters": [
                     {
-                        "description": "Agent Credentials",
-                        "name": "credentials",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/main.AuthenticationRequestDoc"
-                        }
+                        "type": "integer",
+                        "description": "Mission ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Created",
+                    "200": {
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/main.TokenResponseDoc"
+                            "$ref": "#/definitions/main.MissionResponseDoc"
                         }
                     },
-                    "400": {
-                        "description": "Bad Request",
+                    "401": {
+                        "description": "Unauthorized",
                         "schema": {
                             "$ref": "#/definitions/main.ErrorResponseDoc"
                         }
                     },
-                    "401": {
-                        "description": "Unauthorized",
+                    "403": {
+                        "description": "Forbidden",
                         "schema": {
                             "$ref": "#/definitions/main.ErrorResponseDoc"
                         }
                     },
-                    "422": {
-                        "description": "Unprocessable Entity",
+                    "404": {
+                        "description": "Not Found",
                         "schema": {
-                            "$ref": "#/definitions/main.ValidationErrorResponseDoc"
+                            "$ref": "#/definitions/main.ErrorResponseDoc"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/main.ErrorResponseDoc"
                         }
                     },
                     "500": {
@@ -1006,9 +1174,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/tokens/authentication/spy-cats": {
-            "post": {
-                "description": "Authenticate a spy cat and return a JWT token",
+        "/missions/{id}/fail": {
+            "patch": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Mark a mission in progress as failed, the reason is required",
                 "consumes": [
                     "application/json"
                 ],
@@ -1016,25 +1189,32 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "authentication"
+                    "missions"
                 ],
-                "summary": "Create spy cat authentication token",
+                "summary": "Fail a mission",
                 "parameters": [
                     {
-                        "description": "Spy Cat Credentials",
-                        "name": "credentials",
+                        "type": "integer",
+                        "description": "Mission ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Failure Reason",
+                        "name": "reason",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/main.AuthenticationRequestDoc"
+                            "$ref": "#/definitions/main.MissionReasonRequestDoc"
                         }
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Created",
+                    "200": {
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/main.TokenResponseDoc"
+                            "$ref": "#/definitions/main.MissionResponseDoc"
                         }
                     },
                     "400": {
@@ -1049,200 +1229,2378 @@ const docTemplate = `{
                             "$ref": "#/definitions/main.ErrorResponseDoc"
                         }
                     },
-                    "422": {
-                        "description": "Unprocessable Entity",
+                    "403": {
+                        "description": "Forbidden",
                         "schema": {
-                            "$ref": "#/definitions/main.ValidationErrorResponseDoc"
+                            "$ref": "#/definitions/main.ErrorResponseDoc"
                         }
                     },
-                    "500": {
-                        "description": "Internal Server Error",
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/main.ErrorResponseDoc"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/main.ErrorResponseDoc"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/main.ValidationErrorResponseDoc"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/main.ErrorResponseDoc"
+                        }
+                    }
+                }
+            }
+        },
+        "/missions/{id}/handoff": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Move a mission in progress to another available spy cat. Completed targets stay as they are and the handoff is recorded with the agent who made it.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "missions"
+                ],
+                "summary": "Hand off mission to another spy cat",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Mission ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Handoff Details",
+                        "name": "handoff",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/main.HandOffMissionRequestDoc"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/main.MissionResponseDoc"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/main.ErrorResponseDoc"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/main.ErrorResponseDoc"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/main.ErrorResponseDoc"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/main.ErrorResponseDoc"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/main.ErrorResponseDoc"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/main.ValidationErrorResponseDoc"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/main.ErrorResponseDoc"
+                        }
+                    }
+                }
+            }
+        },
+        "/missions/{id}/handoffs": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get the spy cats a mission was taken from, to whom it was given and by which agent",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "missions"
+                ],
+                "summary": "List mission handoffs",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Mission ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/main.MissionHandoffsResponseDoc"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/main.ErrorResponseDoc"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/main.ErrorResponseDoc"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/main.ErrorResponseDoc"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/main.ErrorResponseDoc"
+                        }
+                    }
+                }
+            }
+        },
+        "/missions/{id}/reopen": {
+            "patch": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Bring a completed, aborted or failed mission back to created. The spy cat is released and unfinished targets return to created. If every target is completed, they all return to created.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "missions"
+                ],
+                "summary": "Reopen a mission",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Mission ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/main.MissionResponseDoc"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/main.ErrorResponseDoc"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/main.ErrorResponseDoc"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/main.ErrorResponseDoc"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/main.ErrorResponseDoc"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/main.ErrorResponseDoc"
+                        }
+                    }
+                }
+            }
+        },
+        "/missions/{id}/spy-cat": {
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Take a mission back from its spy cat. The mission returns to created and its targets keep their notes.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "missions"
+                ],
+                "summary": "Unassign mission from spy cat",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Mission ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/main.MissionResponseDoc"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/main.ErrorResponseDoc"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/main.ErrorResponseDoc"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/main.ErrorResponseDoc"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/main.ErrorResponseDoc"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/main.ErrorResponseDoc"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/main.ErrorResponseDoc"
+                        }
+                    }
+                }
+            }
+        },
+        "/missions/{id}/spy-cat/{spy-cat-id}": {
+            "patch": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Assign a mission to a specific spy cat",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "missions"
+                ],
+                "summary": "Assign mission to spy cat",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Mission ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Spy Cat ID",
+                        "name": "spy-cat-id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/main.MissionResponseDoc"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/main.ErrorResponseDoc"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/main.ErrorResponseDoc"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/main.ErrorResponseDoc"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/main.ErrorResponseDoc"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/main.ErrorResponseDoc"
+                        }
+                    }
+                }
+            }
+        },
+        "/missions/{id}/targets": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Add a new target to a mission",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "missions"
+                ],
+                "summary": "Create mission target",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Mission ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Target Details",
+                        "name": "target",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/main.CreateTargetRequestDoc"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/main.TargetResponseDoc"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/main.ErrorResponseDoc"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/main.ErrorResponseDoc"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/main.ErrorResponseDoc"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/main.ErrorResponseDoc"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/main.ErrorResponseDoc"
+                        }
+                    }
+                }
+            }
+        },
+        "/missions/{id}/targets/{target-id}": {
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Delete a mission target by ID",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "missions"
+                ],
+                "summary": "Delete mission target",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Mission ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Target ID",
+                        "name": "target-id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/main.MessageResponseDoc"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/main.ErrorResponseDoc"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/main.ErrorResponseDoc"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/main.ErrorResponseDoc"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/main.ErrorResponseDoc"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Update the notes of a mission target",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "missions"
+                ],
+                "summary": "Update mission target",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Mission ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Target ID",
+                        "name": "target-id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Target Notes",
+                        "name": "notes",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/main.UpdateTargetNotesRequestDoc"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/main.TargetResponseDoc"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/main.ErrorResponseDoc"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/main.ErrorResponseDoc"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/main.ErrorResponseDoc"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/main.ErrorResponseDoc"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/main.ErrorResponseDoc"
+                        }
+                    }
+                }
+            }
+        },
+        "/missions/{id}/targets/{target-id}/complete": {
+            "patch": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Mark a target of a mission that is not finished as completed",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "missions"
+                ],
+                "summary": "Complete mission target",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Mission ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Target ID",
+                        "name": "target-id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/main.TargetResponseDoc"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/main.ErrorResponseDoc"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/main.ErrorResponseDoc"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/main.ErrorResponseDoc"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/main.ErrorResponseDoc"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/main.ErrorResponseDoc"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/main.ErrorResponseDoc"
+                        }
+                    }
+                }
+            }
+        },
+        "/readyz": {
+            "get": {
+                "description": "Report whether the database and the breed list are available. Becomes not ready once shutdown starts. Failure details are logged, not returned.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "health"
+                ],
+                "summary": "Readiness check",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/main.ReadinessResponseDoc"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/main.ErrorResponseDoc"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/main.ReadinessResponseDoc"
+                        }
+                    }
+                }
+            }
+        },
+        "/reports/payroll": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get the monthly salary cost per breed and experience band for a range of months. Salaries are annual, so a month costs a twelfth of the salary in effect on its first day. Spy cats are charged for every month they were on the books in, from the month they were created in to the month they were archived in, grouped by their current breed and experience band.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "spy-cats"
+                ],
+                "summary": "Get payroll report",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "First month (YYYY-MM)",
+                        "name": "from",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Last month (YYYY-MM)",
+                        "name": "to",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/main.PayrollResponseDoc"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/main.ErrorResponseDoc"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/main.ErrorResponseDoc"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/main.ValidationErrorResponseDoc"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/main.ErrorResponseDoc"
+                        }
+                    }
+                }
+            }
+        },
+        "/spy-cats": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get a paginated list of spy cats, optionally filtered and sorted",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "spy-cats"
+                ],
+                "summary": "List all spy cats",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "default": 1,
+                        "description": "Page number",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 20,
+                        "description": "Page size",
+                        "name": "page_size",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "id",
+                            "name",
+                            "years_of_experience",
+                            "salary",
+                            "-id",
+                            "-name",
+                            "-years_of_experience",
+                            "-salary"
+                        ],
+                        "type": "string",
+                        "description": "Sort field, prefix with - for descending. Salaries are sorted by currency first",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Breed name or TheCatAPI id, case-insensitive",
+                        "name": "breed",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Minimum years of experience",
+                        "name": "min_experience",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Maximum years of experience",
+                        "name": "max_experience",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "default": "USD",
+                        "description": "Currency of min_salary and max_salary",
+                        "name": "currency",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Minimum salary, a decimal in the currency",
+                        "name": "min_salary",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Maximum salary, a decimal in the currency",
+                        "name": "max_salary",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "active",
+                            "archived",
+                            "all"
+                        ],
+                        "type": "string",
+                        "default": "active",
+                        "description": "Archive status",
+                        "name": "status",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/main.SpyCatsResponseDoc"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/main.ErrorResponseDoc"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/main.ErrorResponseDoc"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/main.ValidationErrorResponseDoc"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/main.ErrorResponseDoc"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Create a new spy cat with the provided details. The salary is annual.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "spy-cats"
+                ],
+                "summary": "Create a new spy cat",
+                "parameters": [
+                    {
+                        "description": "Spy Cat Details",
+                        "name": "spy-cat",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/main.CreateSpyCatRequestDoc"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/main.SpyCatResponseDoc"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/main.ErrorResponseDoc"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/main.ErrorResponseDoc"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/main.ErrorResponseDoc"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/main.ValidationErrorResponseDoc"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/main.ErrorResponseDoc"
+                        }
+                    }
+                }
+            }
+        },
+        "/spy-cats/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get details of a specific spy cat by ID",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "spy-cats"
+                ],
+                "summary": "Get a spy cat by ID",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Spy Cat ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/main.SpyCatResponseDoc"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/main.ErrorResponseDoc"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/main.ErrorResponseDoc"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/main.ErrorResponseDoc"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/main.ErrorResponseDoc"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Archive a spy cat by ID. Archived spy cats are hidden from lists and can't log in, but stay in the mission history. A spy cat with a mission in progress can't be deleted.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "spy-cats"
+                ],
+                "summary": "Delete a spy cat",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Spy Cat ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/main.MessageResponseDoc"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/main.ErrorResponseDoc"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/main.ErrorResponseDoc"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/main.ErrorResponseDoc"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/main.ErrorResponseDoc"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/main.ErrorResponseDoc"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Update the profile of a specific spy cat with JSON Merge Patch (RFC 7396), fields absent from the body are left untouched. No field can be removed, so a null value is rejected with 422. A salary change is recorded in the salary history, it must not be effective before the latest recorded change.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "spy-cats"
+                ],
+                "summary": "Update a spy cat",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Spy Cat ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Spy Cat Update",
+                        "name": "spy-cat",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/main.UpdateSpyCatRequestDoc"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/main.SpyCatResponseDoc"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/main.ErrorResponseDoc"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/main.ErrorResponseDoc"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/main.ErrorResponseDoc"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/main.ErrorResponseDoc"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/main.ErrorResponseDoc"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/main.ValidationErrorResponseDoc"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/main.ErrorResponseDoc"
+                        }
+                    }
+                }
+            }
+        },
+        "/spy-cats/{id}/password-reset": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Issue a single-use token which allows the spy cat to set a new password",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "spy-cats"
+                ],
+                "summary": "Create spy cat password reset token",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Spy Cat ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/main.PasswordResetTokenResponseDoc"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/main.ErrorResponseDoc"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/main.ErrorResponseDoc"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/main.ErrorResponseDoc"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/main.ErrorResponseDoc"
+                        }
+                    }
+                }
+            }
+        },
+        "/spy-cats/{id}/restore": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Restore an archived spy cat by ID",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "spy-cats"
+                ],
+                "summary": "Restore a spy cat",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Spy Cat ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/main.SpyCatResponseDoc"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/main.ErrorResponseDoc"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/main.ErrorResponseDoc"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/main.ErrorResponseDoc"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/main.ErrorResponseDoc"
+                        }
+                    }
+                }
+            }
+        },
+        "/spy-cats/{id}/salary-history": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get all salary changes of a specific spy cat ordered by effective date",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "spy-cats"
+                ],
+                "summary": "Get spy cat salary history",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Spy Cat ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/main.SalaryHistoryResponseDoc"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/main.ErrorResponseDoc"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/main.ErrorResponseDoc"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/main.ErrorResponseDoc"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/main.ErrorResponseDoc"
+                        }
+                    }
+                }
+            }
+        },
+        "/spy-cats/{id}/tokens": {
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Revoke all tokens issued to a specific spy cat",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "spy-cats"
+                ],
+                "summary": "Revoke spy cat tokens",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Spy Cat ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/main.MessageResponseDoc"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/main.ErrorResponseDoc"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/main.ErrorResponseDoc"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/main.ErrorResponseDoc"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/main.ErrorResponseDoc"
+                        }
+                    }
+                }
+            }
+        },
+        "/tokens/authentication": {
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Log out by revoking the authentication token used for this request",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "authentication"
+                ],
+                "summary": "Revoke current authentication token",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/main.MessageResponseDoc"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/main.ErrorResponseDoc"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/main.ErrorResponseDoc"
+                        }
+                    }
+                }
+            }
+        },
+        "/tokens/authentication/agents": {
+            "post": {
+                "description": "Authenticate an agent and return a JWT token",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "authentication"
+                ],
+                "summary": "Create agent authentication token",
+                "parameters": [
+                    {
+                        "description": "Agent Credentials",
+                        "name": "credentials",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/main.AuthenticationRequestDoc"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/main.TokenResponseDoc"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/main.ErrorResponseDoc"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/main.ErrorResponseDoc"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/main.ValidationErrorResponseDoc"
+                        }
+                    },
+                    "429": {
+                        "description": "Too Many Requests",
+                        "schema": {
+                            "$ref": "#/definitions/main.ErrorResponseDoc"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/main.ErrorResponseDoc"
+                        }
+                    }
+                }
+            }
+        },
+        "/tokens/authentication/spy-cats": {
+            "post": {
+                "description": "Authenticate a spy cat and return a JWT token",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "authentication"
+                ],
+                "summary": "Create spy cat authentication token",
+                "parameters": [
+                    {
+                        "description": "Spy Cat Credentials",
+                        "name": "credentials",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/main.AuthenticationRequestDoc"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/main.TokenResponseDoc"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/main.ErrorResponseDoc"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/main.ErrorResponseDoc"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/main.ValidationErrorResponseDoc"
+                        }
+                    },
+                    "429": {
+                        "description": "Too Many Requests",
+                        "schema": {
+                            "$ref": "#/definitions/main.ErrorResponseDoc"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/main.ErrorResponseDoc"
+                        }
+                    }
+                }
+            }
+        },
+        "/tokens/invitation": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Create a single-use invitation token which allows a new agent to register",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "agents"
+                ],
+                "summary": "Create an agent invitation",
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/main.InvitationTokenResponseDoc"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/main.ErrorResponseDoc"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/main.ErrorResponseDoc"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/main.ErrorResponseDoc"
+                        }
+                    }
+                }
+            }
+        },
+        "/tokens/password-reset": {
+            "put": {
+                "description": "Set a new password using a password reset token. All existing tokens of the user are revoked.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "authentication"
+                ],
+                "summary": "Reset password",
+                "parameters": [
+                    {
+                        "description": "Reset token and new password",
+                        "name": "reset",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/main.ResetPasswordRequestDoc"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/main.MessageResponseDoc"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/main.ErrorResponseDoc"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/main.ValidationErrorResponseDoc"
+                        }
+                    },
+                    "429": {
+                        "description": "Too Many Requests",
                         "schema": {
                             "$ref": "#/definitions/main.ErrorResponseDoc"
                         }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/main.ErrorResponseDoc"
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "main.AgentDoc": {
+            "description": "Agent entity",
+            "type": "object",
+            "properties": {
+                "id": {
+                    "description": "Unique identifier\nExample: 1",
+                    "type": "integer"
+                },
+                "name": {
+                    "description": "Agent name\nExample: Agent Smith",
+                    "type": "string"
+                },
+                "role": {
+                    "description": "Agent role (admin, handler, analyst)\nExample: analyst",
+                    "type": "string"
+                }
+            }
+        },
+        "main.AgentResponseDoc": {
+            "description": "Response containing a single agent",
+            "type": "object",
+            "properties": {
+                "agent": {
+                    "description": "Agent data",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/main.AgentDoc"
+                        }
+                    ]
+                }
+            }
+        },
+        "main.AuthenticationRequestDoc": {
+            "description": "Request body for user authentication",
+            "type": "object",
+            "properties": {
+                "name": {
+                    "description": "User name\nExample: Agent Smith",
+                    "type": "string"
+                },
+                "password": {
+                    "description": "User password\nExample: password123",
+                    "type": "string"
+                }
+            }
+        },
+        "main.BreedDoc": {
+            "description": "A cat breed as known to TheCatAPI",
+            "type": "object",
+            "properties": {
+                "id": {
+                    "description": "TheCatAPI breed ID\nExample: siam",
+                    "type": "string"
+                },
+                "life_span": {
+                    "description": "Life span in years\nExample: 12 - 15",
+                    "type": "string"
+                },
+                "name": {
+                    "description": "Breed name\nExample: Siamese",
+                    "type": "string"
+                },
+                "origin": {
+                    "description": "Country of origin\nExample: Thailand",
+                    "type": "string"
+                },
+                "temperament": {
+                    "description": "Comma separated temperament traits\nExample: Active, Agile, Clever",
+                    "type": "string"
+                }
+            }
+        },
+        "main.BreedResponseDoc": {
+            "description": "Response containing a single breed",
+            "type": "object",
+            "properties": {
+                "breed": {
+                    "description": "Breed data",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/main.BreedDoc"
+                        }
+                    ]
+                }
+            }
+        },
+        "main.BreedsResponseDoc": {
+            "description": "Response containing the breeds a spy cat can be of",
+            "type": "object",
+            "properties": {
+                "breeds": {
+                    "description": "List of breeds",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/main.BreedDoc"
+                    }
+                }
+            }
+        },
+        "main.ChangeAgentRoleRequestDoc": {
+            "description": "Request body for changing an agent role",
+            "type": "object",
+            "properties": {
+                "role": {
+                    "description": "Agent role (admin, handler, analyst)\nExample: handler",
+                    "type": "string"
+                }
+            }
+        },
+        "main.ChangePasswordRequestDoc": {
+            "description": "Request body for changing the password of the authenticated user",
+            "type": "object",
+            "properties": {
+                "current_password": {
+                    "description": "Current password\nExample: password123",
+                    "type": "string"
+                },
+                "password": {
+                    "description": "New password\nExample: newpassword123",
+                    "type": "string"
+                }
+            }
+        },
+        "main.CreateAgentRequestDoc": {
+            "description": "Request body for redeeming an invitation and creating a new agent",
+            "type": "object",
+            "properties": {
+                "name": {
+                    "description": "Agent name\nExample: Agent Smith",
+                    "type": "string"
+                },
+                "password": {
+                    "description": "Password for authentication\nExample: agentpassword123",
+                    "type": "string"
+                },
+                "token": {
+                    "description": "Invitation token\nExample: ABCDEFGHIJKLMNOPQRSTUVWXYZ",
+                    "type": "string"
+                }
+            }
+        },
+        "main.CreateMissionRequestDoc": {
+            "description": "Request body for creating a new mission",
+            "type": "object",
+            "properties": {
+                "targets": {
+                    "description": "List of targets for the mission",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/main.CreateTargetRequestDoc"
+                    }
+                }
+            }
+        },
+        "main.CreateSpyCatRequestDoc": {
+            "description": "Request body for creating a new spy cat",
+            "type": "object",
+            "properties": {
+                "breed": {
+                    "description": "Cat breed name or TheCatAPI ID, matched ignoring case and extra whitespace\nExample: Siamese",
+                    "type": "string"
+                },
+                "name": {
+                    "description": "Spy cat name\nExample: Agent Whiskers",
+                    "type": "string"
+                },
+                "password": {
+                    "description": "Password for authentication\nExample: secretpassword123",
+                    "type": "string"
+                },
+                "salary": {
+                    "description": "Annual salary",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/main.MoneyDoc"
+                        }
+                    ]
+                },
+                "years_of_experience": {
+                    "description": "Years of experience\nExample: 5",
+                    "type": "integer"
+                }
+            }
+        },
+        "main.CreateTargetRequestDoc": {
+            "description": "Request body for creating a new target",
+            "type": "object",
+            "properties": {
+                "country": {
+                    "description": "Target country\nExample: Switzerland",
+                    "type": "string"
+                },
+                "name": {
+                    "description": "Target name\nExample: Dr. Evil",
+                    "type": "string"
+                }
+            }
+        },
+        "main.CursorMetadataDoc": {
+            "description": "Keyset pagination metadata of a list response",
+            "type": "object",
+            "properties": {
+                "limit": {
+                    "description": "Page size\nExample: 20",
+                    "type": "integer"
+                },
+                "next_cursor": {
+                    "description": "Cursor of the next page, omitted on the last page\nExample: MQ",
+                    "type": "string"
+                }
+            }
+        },
+        "main.ErrorResponseDoc": {
+            "description": "Standard error response format",
+            "type": "object",
+            "properties": {
+                "error": {
+                    "description": "Error message\nExample: error message",
+                    "type": "string"
+                }
+            }
+        },
+        "main.HandOffMissionRequestDoc": {
+            "description": "Request body for handing off a mission to another spy cat",
+            "type": "object",
+            "properties": {
+                "spy_cat_id": {
+                    "description": "ID of the spy cat taking over the mission\nExample: 2",
+                    "type": "integer"
+                }
+            }
+        },
+        "main.HealthcheckResponseDoc": {
+            "description": "Liveness check response",
+            "type": "object",
+            "properties": {
+                "status": {
+                    "description": "Process status\nExample: available",
+                    "type": "string"
+                },
+                "system_info": {
+                    "description": "Environment and version of the running process",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "main.InvitationTokenResponseDoc": {
+            "description": "Response containing an agent invitation token",
+            "type": "object",
+            "properties": {
+                "invitation_token": {
+                    "description": "Invitation token data",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/main.TokenDoc"
+                        }
+                    ]
+                }
+            }
+        },
+        "main.LockoutDoc": {
+            "description": "Failed logins of a name",
+            "type": "object",
+            "properties": {
+                "failures": {
+                    "description": "Number of consecutive failed logins\nExample: 5",
+                    "type": "integer"
+                },
+                "last_failure_at": {
+                    "description": "Time of the last failed login\nExample: 2024-01-01T00:00:00Z",
+                    "type": "string"
+                },
+                "locked_until": {
+                    "description": "Time until which logins are rejected\nExample: 2024-01-01T00:00:30Z",
+                    "type": "string"
+                },
+                "name": {
+                    "description": "Login name\nExample: Agent Smith",
+                    "type": "string"
+                },
+                "user_type": {
+                    "description": "User type (spy-cat, agent)\nExample: agent",
+                    "type": "string"
+                }
+            }
+        },
+        "main.LockoutsResponseDoc": {
+            "description": "Response containing the currently locked out names",
+            "type": "object",
+            "properties": {
+                "lockouts": {
+                    "description": "List of lockouts",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/main.LockoutDoc"
                     }
                 }
             }
-        }
-    },
-    "definitions": {
-        "main.AgentDoc": {
-            "description": "Agent entity",
+        },
+        "main.MessageResponseDoc": {
+            "description": "Simple message response format",
+            "type": "object",
+            "properties": {
+                "message": {
+                    "description": "Success message\nExample: operation completed successfully",
+                    "type": "string"
+                }
+            }
+        },
+        "main.MetadataDoc": {
+            "description": "Pagination metadata of a list response",
+            "type": "object",
+            "properties": {
+                "current_page": {
+                    "description": "Current page number\nExample: 1",
+                    "type": "integer"
+                },
+                "first_page": {
+                    "description": "First page number\nExample: 1",
+                    "type": "integer"
+                },
+                "last_page": {
+                    "description": "Last page number\nExample: 1",
+                    "type": "integer"
+                },
+                "page_size": {
+                    "description": "Page size\nExample: 20",
+                    "type": "integer"
+                },
+                "total_records": {
+                    "description": "Total number of records matching the filters\nExample: 1",
+                    "type": "integer"
+                }
+            }
+        },
+        "main.MissionDoc": {
+            "description": "Mission entity",
             "type": "object",
             "properties": {
+                "assigned_cat_id": {
+                    "description": "ID of assigned spy cat\nExample: 1",
+                    "type": "integer"
+                },
                 "id": {
                     "description": "Unique identifier\nExample: 1",
                     "type": "integer"
                 },
-                "name": {
-                    "description": "Agent name\nExample: Agent Smith",
+                "reason": {
+                    "description": "Reason the mission was aborted or failed\nExample: the target has gone into hiding",
                     "type": "string"
+                },
+                "state": {
+                    "description": "Mission state (created, in_progress, completed, aborted, failed)\nExample: created",
+                    "type": "string"
+                },
+                "targets": {
+                    "description": "List of mission targets",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/main.TargetDoc"
+                    }
                 }
             }
         },
-        "main.AgentResponseDoc": {
-            "description": "Response containing a single agent",
+        "main.MissionHandoffDoc": {
+            "description": "A change of the spy cat assigned to a mission",
             "type": "object",
             "properties": {
-                "agent": {
-                    "description": "Agent data",
+                "agent_id": {
+                    "description": "Agent who made the handoff\nExample: 1",
+                    "type": "integer"
+                },
+                "created_at": {
+                    "description": "Time of the handoff\nExample: 2024-01-01T10:00:00Z",
+                    "type": "string"
+                },
+                "from_spy_cat_id": {
+                    "description": "Spy cat the mission was taken from\nExample: 1",
+                    "type": "integer"
+                },
+                "id": {
+                    "description": "Handoff ID\nExample: 1",
+                    "type": "integer"
+                },
+                "mission_id": {
+                    "description": "Mission ID\nExample: 1",
+                    "type": "integer"
+                },
+                "to_spy_cat_id": {
+                    "description": "Spy cat the mission was given to, absent when it was unassigned\nExample: 2",
+                    "type": "integer"
+                }
+            }
+        },
+        "main.MissionHandoffsResponseDoc": {
+            "description": "Response containing the handoffs of a mission",
+            "type": "object",
+            "properties": {
+                "handoffs": {
+                    "description": "List of handoffs",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/main.MissionHandoffDoc"
+                    }
+                }
+            }
+        },
+        "main.MissionReasonRequestDoc": {
+            "description": "Request body with the reason a mission is aborted or failed",
+            "type": "object",
+            "properties": {
+                "reason": {
+                    "description": "Reason of the abort or failure\nExample: the target has gone into hiding",
+                    "type": "string"
+                }
+            }
+        },
+        "main.MissionResponseDoc": {
+            "description": "Response containing a single mission",
+            "type": "object",
+            "properties": {
+                "mission": {
+                    "description": "Mission data",
                     "allOf": [
                         {
-                            "$ref": "#/definitions/main.AgentDoc"
+                            "$ref": "#/definitions/main.MissionDoc"
                         }
                     ]
                 }
             }
         },
-        "main.AuthenticationRequestDoc": {
-            "description": "Request body for user authentication",
+        "main.MissionsResponseDoc": {
+            "description": "Response containing a page of missions with cursor metadata",
             "type": "object",
             "properties": {
-                "name": {
-                    "description": "User name\nExample: Agent Smith",
-                    "type": "string"
+                "metadata": {
+                    "description": "Cursor metadata",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/main.CursorMetadataDoc"
+                        }
+                    ]
                 },
-                "password": {
-                    "description": "User password\nExample: password123",
-                    "type": "string"
+                "missions": {
+                    "description": "List of missions",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/main.MissionDoc"
+                    }
                 }
             }
         },
-        "main.CreateAgentRequestDoc": {
-            "description": "Request body for creating a new agent",
+        "main.MoneyDoc": {
+            "description": "Amount of money in an ISO 4217 currency (EUR, GBP, JPY, UAH, USD)",
             "type": "object",
             "properties": {
-                "name": {
-                    "description": "Agent name\nExample: Agent Smith",
+                "amount": {
+                    "description": "Decimal amount with at most as many fractional digits as the currency has\nExample: 50000.00",
                     "type": "string"
                 },
-                "password": {
-                    "description": "Password for authentication\nExample: agentpassword123",
+                "currency": {
+                    "description": "ISO 4217 currency code\nExample: USD",
                     "type": "string"
                 }
             }
         },
-        "main.CreateMissionRequestDoc": {
-            "description": "Request body for creating a new mission",
+        "main.PasswordResetTokenResponseDoc": {
+            "description": "Response containing a password reset token",
             "type": "object",
             "properties": {
-                "targets": {
-                    "description": "List of targets for the mission",
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/main.CreateTargetRequestDoc"
-                    }
+                "password_reset_token": {
+                    "description": "Password reset token data",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/main.TokenDoc"
+                        }
+                    ]
                 }
             }
         },
-        "main.CreateSpyCatRequestDoc": {
-            "description": "Request body for creating a new spy cat",
+        "main.PayrollDoc": {
+            "description": "Monthly salary cost per breed and experience band for a range of months",
             "type": "object",
             "properties": {
-                "breed": {
-                    "description": "Cat breed\nExample: Siamese",
+                "from": {
+                    "description": "First month\nExample: 2024-01",
                     "type": "string"
                 },
-                "name": {
-                    "description": "Spy cat name\nExample: Agent Whiskers",
-                    "type": "string"
+                "rows": {
+                    "description": "Cost per month, breed, experience band and currency",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/main.PayrollRowDoc"
+                    }
                 },
-                "password": {
-                    "description": "Password for authentication\nExample: secretpassword123",
+                "to": {
+                    "description": "Last month\nExample: 2024-03",
                     "type": "string"
                 },
-                "salary": {
-                    "description": "Annual salary\nExample: 50000.00",
-                    "type": "number"
-                },
-                "years_of_experience": {
-                    "description": "Years of experience\nExample: 5",
-                    "type": "integer"
+                "totals": {
+                    "description": "Total cost of the range per currency",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/main.MoneyDoc"
+                    }
                 }
             }
         },
-        "main.CreateTargetRequestDoc": {
-            "description": "Request body for creating a new target",
+        "main.PayrollResponseDoc": {
+            "description": "Response containing the monthly salary cost per breed and experience band",
             "type": "object",
             "properties": {
-                "country": {
-                    "description": "Target country\nExample: Switzerland",
+                "payroll": {
+                    "description": "Payroll report",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/main.PayrollDoc"
+                        }
+                    ]
+                }
+            }
+        },
+        "main.PayrollRowDoc": {
+            "description": "Salary cost of the spy cats of a breed and experience band in a month",
+            "type": "object",
+            "properties": {
+                "breed": {
+                    "description": "Breed\nExample: Siamese",
                     "type": "string"
                 },
-                "name": {
-                    "description": "Target name\nExample: Dr. Evil",
+                "cost": {
+                    "description": "Salary cost of the month",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/main.MoneyDoc"
+                        }
+                    ]
+                },
+                "experience_band": {
+                    "description": "Experience band (0-2, 3-5, 6-10, 11+ years)\nExample: 3-5",
+                    "type": "string"
+                },
+                "month": {
+                    "description": "Month\nExample: 2024-01",
                     "type": "string"
+                },
+                "spy_cats": {
+                    "description": "Number of spy cats\nExample: 2",
+                    "type": "integer"
                 }
             }
         },
-        "main.ErrorResponseDoc": {
-            "description": "Standard error response format",
+        "main.ReadinessResponseDoc": {
+            "description": "Readiness check response with the state of every dependency",
             "type": "object",
             "properties": {
-                "error": {
-                    "description": "Error message\nExample: error message",
+                "checks": {
+                    "description": "State of every dependency (up, unavailable, shutting down)",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "status": {
+                    "description": "Readiness status (ready, not ready)\nExample: ready",
                     "type": "string"
                 }
             }
         },
-        "main.MessageResponseDoc": {
-            "description": "Simple message response format",
+        "main.ResetPasswordRequestDoc": {
+            "description": "Request body for setting a new password with a reset token",
             "type": "object",
             "properties": {
-                "message": {
-                    "description": "Success message\nExample: operation completed successfully",
+                "password": {
+                    "description": "New password\nExample: newpassword123",
+                    "type": "string"
+                },
+                "token": {
+                    "description": "Password reset token\nExample: ABCDEFGHIJKLMNOPQRSTUVWXYZ",
                     "type": "string"
                 }
             }
         },
-        "main.MissionDoc": {
-            "description": "Mission entity",
+        "main.SalaryChangeDoc": {
+            "description": "A change of the salary of a spy cat",
             "type": "object",
             "properties": {
-                "assigned_cat_id": {
-                    "description": "ID of assigned spy cat\nExample: 1",
+                "agent_id": {
+                    "description": "ID of the agent who changed the salary\nExample: 1",
                     "type": "integer"
                 },
+                "created_at": {
+                    "description": "Time the change was recorded\nExample: 2023-12-20T10:00:00Z",
+                    "type": "string"
+                },
+                "effective_date": {
+                    "description": "Date from which the new salary applies\nExample: 2024-01-01T00:00:00Z",
+                    "type": "string"
+                },
                 "id": {
-                    "description": "Unique identifier\nExample: 1",
+                    "description": "Salary change ID\nExample: 1",
                     "type": "integer"
                 },
-                "state": {
-                    "description": "Mission state (created, in_progress, completed)\nExample: created",
-                    "type": "string"
+                "new_salary": {
+                    "description": "Annual salary after the change",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/main.MoneyDoc"
+                        }
+                    ]
                 },
-                "targets": {
-                    "description": "List of mission targets",
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/main.TargetDoc"
-                    }
-                }
-            }
-        },
-        "main.MissionResponseDoc": {
-            "description": "Response containing a single mission",
-            "type": "object",
-            "properties": {
-                "mission": {
-                    "description": "Mission data",
+                "old_salary": {
+                    "description": "Annual salary before the change",
                     "allOf": [
                         {
-                            "$ref": "#/definitions/main.MissionDoc"
+                            "$ref": "#/definitions/main.MoneyDoc"
                         }
                     ]
+                },
+                "reason": {
+                    "description": "Reason of the change\nExample: annual review",
+                    "type": "string"
+                },
+                "spy_cat_id": {
+                    "description": "Spy cat ID\nExample: 1",
+                    "type": "integer"
                 }
             }
         },
-        "main.MissionsResponseDoc": {
-            "description": "Response containing a list of missions",
+        "main.SalaryHistoryResponseDoc": {
+            "description": "Response containing the salary changes of a spy cat",
             "type": "object",
             "properties": {
-                "missions": {
-                    "description": "List of missions",
+                "salary_history": {
+                    "description": "List of salary changes",
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/main.MissionDoc"
+                        "$ref": "#/definitions/main.SalaryChangeDoc"
                     }
                 }
             }
@@ -1255,6 +3613,14 @@ const docTemplate = `{
                     "description": "Cat breed\nExample: Siamese",
                     "type": "string"
                 },
+                "created_at": {
+                    "description": "Time the spy cat was created, payroll reports charge it from this month on\nExample: 2023-06-01T10:00:00Z",
+                    "type": "string"
+                },
+                "deleted_at": {
+                    "description": "Time the spy cat was archived, absent for active spy cats\nExample: 2024-02-01T10:00:00Z",
+                    "type": "string"
+                },
                 "id": {
                     "description": "Unique identifier\nExample: 1",
                     "type": "integer"
@@ -1264,8 +3630,12 @@ const docTemplate = `{
                     "type": "string"
                 },
                 "salary": {
-                    "description": "Annual salary\nExample: 50000.00",
-                    "type": "number"
+                    "description": "Annual salary",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/main.MoneyDoc"
+                        }
+                    ]
                 },
                 "years_of_experience": {
                     "description": "Years of experience\nExample: 5",
@@ -1288,9 +3658,17 @@ const docTemplate = `{
             }
         },
         "main.SpyCatsResponseDoc": {
-            "description": "Response containing a list of spy cats",
+            "description": "Response containing a page of spy cats with pagination metadata",
             "type": "object",
             "properties": {
+                "metadata": {
+                    "description": "Pagination metadata",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/main.MetadataDoc"
+                        }
+                    ]
+                },
                 "spy-cats": {
                     "description": "List of spy cats",
                     "type": "array",
@@ -1380,13 +3758,37 @@ const docTemplate = `{
                 }
             }
         },
-        "main.UpdateSpyCatSalaryRequestDoc": {
-            "description": "Request body for updating a spy cat's salary",
+        "main.UpdateSpyCatRequestDoc": {
+            "description": "JSON Merge Patch of a spy cat, only the given fields are changed",
             "type": "object",
             "properties": {
+                "breed": {
+                    "description": "Cat breed name or TheCatAPI ID, matched ignoring case and extra whitespace\nExample: Siamese",
+                    "type": "string"
+                },
+                "name": {
+                    "description": "Spy cat name\nExample: Agent Whiskers",
+                    "type": "string"
+                },
                 "salary": {
-                    "description": "New salary amount\nExample: 55000.00",
-                    "type": "number"
+                    "description": "New annual salary",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/main.MoneyDoc"
+                        }
+                    ]
+                },
+                "salary_effective_date": {
+                    "description": "Date from which the new salary applies, only with salary, defaults to today, not before the latest salary change\nExample: 2024-01-01",
+                    "type": "string"
+                },
+                "salary_reason": {
+                    "description": "Reason of the salary change, only with salary\nExample: annual review",
+                    "type": "string"
+                },
+                "years_of_experience": {
+                    "description": "Years of experience\nExample: 6",
+                    "type": "integer"
                 }
             }
         },
@@ -1405,7 +3807,7 @@ const docTemplate = `{
             "type": "object",
             "properties": {
                 "error": {
-                    "description": "Map of field names to error messages\nExample: {\"name\": \"must be provided\", \"breed\": \"invalid breed\"}",
+                    "description": "Map of field names to error messages\nExample: {\"name\": \"must be provided\", \"breed\": \"invalid breed, did you mean: Siamese, Somali, Sphynx\"}",
                     "type": "object",
                     "additionalProperties": {
                         "type": "string"
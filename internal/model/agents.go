@@ -9,6 +9,7 @@ var AnonymousAgent = &Agent{}
 type Agent struct {
 	Id       int64    `json:"id"`
 	Name     string   `json:"name"`
+	Role     Role     `json:"role"`
 	Password Password `json:"-"`
 }
 
@@ -16,9 +17,14 @@ func (a *Agent) IsAnonymous() bool {
 	return a == AnonymousAgent
 }
 
+func (a *Agent) HasPermission(permission Permission) bool {
+	return a.Role.HasPermission(permission)
+}
+
 func ValidateAgent(v *validator.Validator, agent *Agent) {
 	v.Check(agent.Name != "", "name", "must be provided")
 	v.Check(len(agent.Name) <= 500, "name", "must be more than 500 bytes long")
+	ValidateRole(v, agent.Role)
 
 	if agent.Password.plaintext != nil {
 		ValidatePasswordPlaintext(v, *agent.Password.plaintext)
@@ -28,3 +34,7 @@ func ValidateAgent(v *validator.Validator, agent *Agent) {
 		panic("missing password hash for user")
 	}
 }
+
+func ValidateRole(v *validator.Validator, role Role) {
+	v.Check(validator.PermittedValue(role, Roles...), "role", "invalid role")
+}
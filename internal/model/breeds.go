@@ -0,0 +1,111 @@
+package model
+
+import (
+	"cmp"
+	"slices"
+	"strings"
+	"unicode/utf8"
+)
+
+// breedSuggestions is the number of closest breed names listed when a breed
+// isn't recognised.
+const breedSuggestions = 3
+
+type Breed struct {
+	Id          string `json:"id"`
+	Name        string `json:"name"`
+	Origin      string `json:"origin"`
+	Temperament string `json:"temperament"`
+	LifeSpan    string `json:"life_span"`
+}
+
+// HasNamePrefix reports whether the breed name starts with prefix, ignoring
+// case. Runes are compared one by one, since case folding may change their
+// length in bytes.
+func (b *Breed) HasNamePrefix(prefix string) bool {
+	name := b.Name
+	for _, p := range prefix {
+		n, size := utf8.DecodeRuneInString(name)
+		if size == 0 || !strings.EqualFold(string(n), string(p)) {
+			return false
+		}
+		name = name[size:]
+	}
+	return true
+}
+
+func BreedNames(breeds []Breed) []string {
+	names := make([]string, len(breeds))
+	for i, breed := range breeds {
+		names[i] = breed.Name
+	}
+	return names
+}
+
+// NormalizeBreed lower-cases a breed name and collapses its whitespace, so
+// " russian  BLUE" and "Russian Blue" compare equal.
+func NormalizeBreed(name string) string {
+	return strings.ToLower(strings.Join(strings.Fields(name), " "))
+}
+
+// FindBreed returns the breed whose name or TheCatAPI id matches name after
+// normalization.
+func FindBreed(breeds []Breed, name string) (*Breed, bool) {
+	name = NormalizeBreed(name)
+	for i := range breeds {
+		if NormalizeBreed(breeds[i].Name) == name || strings.ToLower(breeds[i].Id) == name {
+			return &breeds[i], true
+		}
+	}
+	return nil, false
+}
+
+// ClosestBreedNames returns up to n breed names ordered by their edit distance
+// to name.
+func ClosestBreedNames(breeds []Breed, name string, n int) []string {
+	name = NormalizeBreed(name)
+
+	type candidate struct {
+		name     string
+		distance int
+	}
+	candidates := make([]candidate, len(breeds))
+	for i, breed := range breeds {
+		candidates[i] = candidate{breed.Name, editDistance(name, NormalizeBreed(breed.Name))}
+	}
+
+	slices.SortFunc(candidates, func(a, b candidate) int {
+		return cmp.Or(cmp.Compare(a.distance, b.distance), strings.Compare(a.name, b.name))
+	})
+
+	names := make([]string, 0, n)
+	for _, c := range candidates[:min(n, len(candidates))] {
+		names = append(names, c.name)
+	}
+	return names
+}
+
+// editDistance is the Levenshtein distance between a and b.
+func editDistance(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+
+	prev := make([]int, len(rb)+1)
+	curr := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(ra); i++ {
+		curr[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+
+	return prev[len(rb)]
+}
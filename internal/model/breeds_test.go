@@ -0,0 +1,31 @@
+package model
+
+import "testing"
+
+func TestBreedHasNamePrefix(t *testing.T) {
+	tc := []struct {
+		name     string
+		prefix   string
+		expected bool
+	}{
+		{"Siamese", "", true},
+		{"Siamese", "sia", true},
+		{"Siamese", "SIAMESE", true},
+		{"Siamese", "Siamese cat", false},
+		{"Siamese", "Bengal", false},
+		{"Égyptienne", "é", true},
+		{"Égyptienne", "e", false},
+		// A prefix that ends inside a multi-byte rune doesn't match.
+		{"Ég", "\xc3", false},
+		// The Kelvin sign folds to k but is 3 bytes long.
+		{"Korat", "\u212ao", true},
+		{"\u212aorat", "ko", true},
+	}
+
+	for _, tt := range tc {
+		breed := Breed{Name: tt.name}
+		if got := breed.HasNamePrefix(tt.prefix); got != tt.expected {
+			t.Errorf("%q.HasNamePrefix(%q) = %t, expected %t", tt.name, tt.prefix, got, tt.expected)
+		}
+	}
+}
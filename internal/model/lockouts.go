@@ -0,0 +1,50 @@
+package model
+
+import (
+	"time"
+
+	"github.com/m1crogravity/spy-cat-agency/internal/validator"
+)
+
+var UserTypes = []UserType{SpyCatUserType, AgentUserType}
+
+// Lockout tracks consecutive failed logins of a name. Names which don't belong
+// to any user are tracked too, so a lockout doesn't reveal whether the user
+// exists.
+type Lockout struct {
+	UserType      UserType  `json:"user_type"`
+	Name          string    `json:"name"`
+	Failures      int       `json:"failures"`
+	LockedUntil   time.Time `json:"locked_until"`
+	LastFailureAt time.Time `json:"last_failure_at"`
+}
+
+func (l *Lockout) IsLocked(now time.Time) bool {
+	return l.LockedUntil.After(now)
+}
+
+// Attempt counts a login attempt as a failure before its password is checked
+// and locks the name for lockFor(failures) when that is positive. A successful
+// login removes the lockout afterwards. Nothing is counted while the name is
+// locked, and false is returned.
+func (l *Lockout) Attempt(now, resetBefore time.Time, lockFor func(int) time.Duration) bool {
+	if l.IsLocked(now) {
+		return false
+	}
+
+	if l.LastFailureAt.Before(resetBefore) {
+		l.Failures = 0
+	}
+	l.Failures++
+	l.LastFailureAt = now
+
+	if delay := lockFor(l.Failures); delay > 0 {
+		l.LockedUntil = now.Add(delay)
+	}
+
+	return true
+}
+
+func ValidateUserType(v *validator.Validator, userType UserType) {
+	v.Check(validator.PermittedValue(userType, UserTypes...), "user_type", "invalid user type")
+}
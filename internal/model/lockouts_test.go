@@ -0,0 +1,49 @@
+package model
+
+import (
+	"testing"
+	"time"
+)
+
+func TestLockoutAttempt(t *testing.T) {
+	lockFor := func(failures int) time.Duration {
+		if failures < 2 {
+			return 0
+		}
+		return time.Duration(failures) * time.Minute
+	}
+	now := time.Now()
+	lockout := &Lockout{LastFailureAt: now}
+
+	if !lockout.Attempt(now, now.Add(-time.Hour), lockFor) {
+		t.Fatal("first attempt was rejected")
+	}
+	if !lockout.Attempt(now, now.Add(-time.Hour), lockFor) {
+		t.Fatal("attempt which reaches the threshold was rejected")
+	}
+	if !lockout.LockedUntil.Equal(now.Add(2 * time.Minute)) {
+		t.Fatalf("expected lock until %s, got %s", now.Add(2*time.Minute), lockout.LockedUntil)
+	}
+	if lockout.Attempt(now.Add(time.Minute), now.Add(-time.Hour), lockFor) {
+		t.Fatal("attempt of a locked name was allowed")
+	}
+	if lockout.Failures != 2 {
+		t.Fatalf("expected 2 failures, got %d", lockout.Failures)
+	}
+
+	later := now.Add(3 * time.Minute)
+	if !lockout.Attempt(later, now.Add(-time.Hour), lockFor) {
+		t.Fatal("attempt after the lock expired was rejected")
+	}
+	if lockout.Failures != 3 || !lockout.LockedUntil.Equal(later.Add(3*time.Minute)) {
+		t.Fatalf("expected 3 failures locked until %s, got %d until %s", later.Add(3*time.Minute), lockout.Failures, lockout.LockedUntil)
+	}
+
+	muchLater := now.Add(24 * time.Hour)
+	if !lockout.Attempt(muchLater, muchLater.Add(-time.Hour), lockFor) {
+		t.Fatal("attempt after the reset was rejected")
+	}
+	if lockout.Failures != 1 {
+		t.Fatalf("expected old failures to be forgotten, got %d", lockout.Failures)
+	}
+}
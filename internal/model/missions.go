@@ -1,16 +1,28 @@
 package model
 
+import (
+	"strings"
+	"time"
+
+	"github.com/m1crogravity/spy-cat-agency/internal/validator"
+)
+
 type CompleteState string
 
 const (
 	Created    CompleteState = "created"
 	InProgress CompleteState = "in_progress"
 	Completed  CompleteState = "completed"
+	Aborted    CompleteState = "aborted"
+	Failed     CompleteState = "failed"
 )
 
+var CompleteStates = []CompleteState{Created, InProgress, Completed, Aborted, Failed}
+
 type Mission struct {
 	Id            int64         `json:"id"`
 	State         CompleteState `json:"state"`
+	Reason        string        `json:"reason,omitempty"`
 	AssignedCatId int64         `json:"assigned_cat_id"`
 	Targets       []*Target     `json:"targets"`
 }
@@ -24,14 +36,26 @@ type Target struct {
 	State     CompleteState `json:"state"`
 }
 
-func (m *Mission) Complete() {
-	m.State = Completed
+// MissionHandoff records who took a mission from one spy cat and to whom it
+// was given. ToSpyCatId is zero when the mission was unassigned.
+type MissionHandoff struct {
+	Id           int64     `json:"id"`
+	MissionId    int64     `json:"mission_id"`
+	FromSpyCatId int64     `json:"from_spy_cat_id"`
+	ToSpyCatId   int64     `json:"to_spy_cat_id,omitempty"`
+	AgentId      int64     `json:"agent_id"`
+	CreatedAt    time.Time `json:"created_at"`
 }
 
 func (m *Mission) IsCompleted() bool {
 	return m.State == Completed
 }
 
+// IsFinished reports whether the mission is completed, aborted or failed.
+func (m *Mission) IsFinished() bool {
+	return m.State == Completed || m.State == Aborted || m.State == Failed
+}
+
 func (m *Mission) IsAssignedToCat() bool {
 	return m.AssignedCatId != 0
 }
@@ -63,10 +87,53 @@ func (t *Target) IsCompleted() bool {
 	return t.State == Completed
 }
 
-func (t *Target) Complete() {
-	t.State = Completed
-}
-
 func (t *Target) UpdateNotes(notes string) {
 	t.Notes = notes
 }
+
+type MissionsFilter struct {
+	State    CompleteState
+	SpyCatId int64
+	Country  string
+	After    int64
+	Limit    int
+}
+
+func (f MissionsFilter) Matches(mission *Mission) bool {
+	switch {
+	case mission.Id <= f.After:
+		return false
+	case f.State != "" && mission.State != f.State:
+		return false
+	case f.SpyCatId != 0 && mission.AssignedCatId != f.SpyCatId:
+		return false
+	case f.Country != "" && !mission.HasTargetIn(f.Country):
+		return false
+	}
+
+	return true
+}
+
+func (m *Mission) HasTargetIn(country string) bool {
+	for _, target := range m.Targets {
+		if strings.EqualFold(target.Country, country) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func ValidateMissionReason(v *validator.Validator, reason string) {
+	v.Check(strings.TrimSpace(reason) != "", "reason", "must be provided")
+	v.Check(len(reason) <= 500, "reason", "must not be more than 500 bytes long")
+}
+
+func ValidateMissionsFilter(v *validator.Validator, f MissionsFilter) {
+	if f.State != "" {
+		v.Check(validator.PermittedValue(f.State, CompleteStates...), "state", "invalid state value")
+	}
+	v.Check(f.SpyCatId >= 0, "spy_cat_id", "must not be negative")
+	v.Check(f.Limit > 0, "limit", "must be greater than zero")
+	v.Check(f.Limit <= 100, "limit", "must be a maximum of 100")
+}
@@ -0,0 +1,133 @@
+package model
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/m1crogravity/spy-cat-agency/internal/validator"
+)
+
+const DefaultCurrency = "USD"
+
+var (
+	ErrUnsupportedCurrency = errors.New("unsupported currency")
+	ErrInvalidAmount       = errors.New("amount must be a decimal string")
+)
+
+type currency struct {
+	// exponent is the number of digits of the minor unit.
+	exponent int
+	// maxSalary is the largest accepted annual salary in minor units.
+	maxSalary int64
+}
+
+var currencies = map[string]currency{
+	"EUR": {exponent: 2, maxSalary: 1_000_000_00},
+	"GBP": {exponent: 2, maxSalary: 1_000_000_00},
+	"JPY": {exponent: 0, maxSalary: 150_000_000},
+	"UAH": {exponent: 2, maxSalary: 40_000_000_00},
+	"USD": {exponent: 2, maxSalary: 1_000_000_00},
+}
+
+// Money is an amount in the minor units of an ISO 4217 currency, e.g. cents
+// of USD. In JSON the amount is a decimal string:
+// {"amount": "55000.00", "currency": "USD"}.
+type Money struct {
+	Amount   int64
+	Currency string
+}
+
+// ParseMoney parses a decimal amount with at most as many fractional digits
+// as the minor unit of the currency has.
+func ParseMoney(amount, code string) (Money, error) {
+	c, ok := currencies[code]
+	if !ok {
+		return Money{}, ErrUnsupportedCurrency
+	}
+
+	digits, negative := strings.CutPrefix(amount, "-")
+	whole, frac, hasFrac := strings.Cut(digits, ".")
+	if whole == "" || !isDigits(whole) || (hasFrac && (frac == "" || !isDigits(frac))) || len(frac) > c.exponent {
+		return Money{}, ErrInvalidAmount
+	}
+
+	minor, err := strconv.ParseInt(whole+frac+strings.Repeat("0", c.exponent-len(frac)), 10, 64)
+	if err != nil {
+		return Money{}, ErrInvalidAmount
+	}
+	if negative {
+		minor = -minor
+	}
+
+	return Money{Amount: minor, Currency: code}, nil
+}
+
+func isDigits(s string) bool {
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// String returns the amount as a decimal, e.g. "55000.00".
+func (m Money) String() string {
+	exponent := currencies[m.Currency].exponent
+
+	sign, amount := "", m.Amount
+	if amount < 0 {
+		sign, amount = "-", -amount
+	}
+
+	s := strconv.FormatInt(amount, 10)
+	if exponent == 0 {
+		return sign + s
+	}
+	if len(s) <= exponent {
+		s = strings.Repeat("0", exponent-len(s)+1) + s
+	}
+
+	return sign + s[:len(s)-exponent] + "." + s[len(s)-exponent:]
+}
+
+type moneyJSON struct {
+	Amount   string `json:"amount"`
+	Currency string `json:"currency"`
+}
+
+func (m Money) MarshalJSON() ([]byte, error) {
+	return json.Marshal(moneyJSON{Amount: m.String(), Currency: m.Currency})
+}
+
+func (m *Money) UnmarshalJSON(b []byte) error {
+	var raw moneyJSON
+	err := json.Unmarshal(b, &raw)
+	if err != nil {
+		return err
+	}
+
+	parsed, err := ParseMoney(raw.Amount, raw.Currency)
+	if err != nil {
+		return fmt.Errorf("invalid money %s %q: %w", raw.Currency, raw.Amount, err)
+	}
+
+	*m = parsed
+	return nil
+}
+
+// ValidateSalary checks that an annual salary is not negative and within the
+// limit of its currency.
+func ValidateSalary(v *validator.Validator, key string, salary Money) {
+	c, ok := currencies[salary.Currency]
+	if !ok {
+		v.AddError(key, "must be provided")
+		return
+	}
+
+	v.Check(salary.Amount >= 0, key, "must not be negative")
+	v.Check(salary.Amount <= c.maxSalary, key, fmt.Sprintf("must not be more than %s %s", Money{c.maxSalary, salary.Currency}, salary.Currency))
+}
@@ -0,0 +1,83 @@
+package model
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/m1crogravity/spy-cat-agency/internal/validator"
+)
+
+func TestParseMoney(t *testing.T) {
+	tests := []struct {
+		amount   string
+		currency string
+		want     Money
+		wantErr  bool
+	}{
+		{amount: "55000", currency: "USD", want: Money{5500000, "USD"}},
+		{amount: "55000.5", currency: "USD", want: Money{5500050, "USD"}},
+		{amount: "0.07", currency: "EUR", want: Money{7, "EUR"}},
+		{amount: "-1.25", currency: "USD", want: Money{-125, "USD"}},
+		{amount: "1500", currency: "JPY", want: Money{1500, "JPY"}},
+		{amount: "1500.5", currency: "JPY", wantErr: true},
+		{amount: "1.255", currency: "USD", wantErr: true},
+		{amount: "1.", currency: "USD", wantErr: true},
+		{amount: "1e3", currency: "USD", wantErr: true},
+		{amount: "", currency: "USD", wantErr: true},
+		{amount: "100", currency: "XXX", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseMoney(tt.amount, tt.currency)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("ParseMoney(%q, %q) expected error", tt.amount, tt.currency)
+			}
+			continue
+		}
+		if err != nil || got != tt.want {
+			t.Errorf("ParseMoney(%q, %q) = %v, %v, want %v", tt.amount, tt.currency, got, err, tt.want)
+		}
+	}
+}
+
+func TestMoneyJSON(t *testing.T) {
+	b, err := json.Marshal(Money{Amount: 5, Currency: "USD"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(b) != `{"amount":"0.05","currency":"USD"}` {
+		t.Fatalf("unexpected JSON %s", b)
+	}
+
+	var m Money
+	err = json.Unmarshal([]byte(`{"amount": "-12.30", "currency": "UAH"}`), &m)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m != (Money{Amount: -1230, Currency: "UAH"}) || m.String() != "-12.30" {
+		t.Fatalf("unexpected money %v", m)
+	}
+}
+
+func TestValidateSalary(t *testing.T) {
+	tests := []struct {
+		salary Money
+		valid  bool
+	}{
+		{Money{5500000, "USD"}, true},
+		{Money{0, "USD"}, true},
+		{Money{-1, "USD"}, false},
+		{Money{1_000_000_01, "USD"}, false},
+		{Money{150_000_000, "JPY"}, true},
+		{Money{}, false},
+	}
+
+	for _, tt := range tests {
+		v := validator.New()
+		ValidateSalary(v, "salary", tt.salary)
+		if v.Valid() != tt.valid {
+			t.Errorf("ValidateSalary(%v %s) valid = %v, want %v", tt.salary, tt.salary.Currency, v.Valid(), tt.valid)
+		}
+	}
+}
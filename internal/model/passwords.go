@@ -49,3 +49,7 @@ func ValidatePasswordPlaintext(v *validator.Validator, password string) {
 	v.Check(len(password) >= 8, "password", "must be at least 8 bytes long")
 	v.Check(len(password) <= 72, "password", "must not be more than 72 bytes long")
 }
+
+// DummyPassword is compared against when the user being authenticated doesn't
+// exist, so that unknown names take as long to reject as wrong passwords.
+var DummyPassword = NewPasswordFromHash([]byte("$2a$12$9BWrdKMlrX2cjtkI/O96qeaStFuAeJ1sTryvqkroAzP8DUByBYfV2"))
@@ -0,0 +1,57 @@
+package model
+
+import "slices"
+
+type Role string
+
+const (
+	RoleAdmin   Role = "admin"
+	RoleHandler Role = "handler"
+	RoleAnalyst Role = "analyst"
+)
+
+var Roles = []Role{RoleAdmin, RoleHandler, RoleAnalyst}
+
+type Permission string
+
+const (
+	PermissionSpyCatsRead   Permission = "spy-cats:read"
+	PermissionSpyCatsWrite  Permission = "spy-cats:write"
+	PermissionMissionsRead  Permission = "missions:read"
+	PermissionMissionsWrite Permission = "missions:write"
+	PermissionAgentsWrite   Permission = "agents:write"
+	PermissionLockoutsRead  Permission = "lockouts:read"
+	PermissionLockoutsWrite Permission = "lockouts:write"
+)
+
+var rolePermissions = map[Role][]Permission{
+	RoleAdmin: {
+		PermissionSpyCatsRead,
+		PermissionSpyCatsWrite,
+		PermissionMissionsRead,
+		PermissionMissionsWrite,
+		PermissionAgentsWrite,
+		PermissionLockoutsRead,
+		PermissionLockoutsWrite,
+	},
+	RoleHandler: {
+		PermissionSpyCatsRead,
+		PermissionMissionsRead,
+		PermissionMissionsWrite,
+		PermissionLockoutsRead,
+		PermissionLockoutsWrite,
+	},
+	RoleAnalyst: {
+		PermissionSpyCatsRead,
+		PermissionMissionsRead,
+		PermissionLockoutsRead,
+	},
+}
+
+func (r Role) Permissions() []Permission {
+	return rolePermissions[r]
+}
+
+func (r Role) HasPermission(permission Permission) bool {
+	return slices.Contains(rolePermissions[r], permission)
+}
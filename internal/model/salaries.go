@@ -0,0 +1,100 @@
+package model
+
+import (
+	"errors"
+	"time"
+
+	"github.com/m1crogravity/spy-cat-agency/internal/validator"
+)
+
+// MaxPayrollMonths bounds the range of a payroll report.
+const MaxPayrollMonths = 120
+
+const MonthLayout = "2006-01"
+
+// ErrBackdatedSalaryChange is returned for a salary change effective before
+// the latest recorded change of the spy cat, as the old salary of every
+// change is the new salary of the one before it.
+var ErrBackdatedSalaryChange = errors.New("salary change is effective before the latest one")
+
+// SalaryChange is an entry of the salary history of a spy cat. Salaries are
+// annual, as the API documents them.
+type SalaryChange struct {
+	Id            int64     `json:"id"`
+	SpyCatId      int64     `json:"spy_cat_id"`
+	AgentId       int64     `json:"agent_id"`
+	OldSalary     Money     `json:"old_salary"`
+	NewSalary     Money     `json:"new_salary"`
+	EffectiveDate time.Time `json:"effective_date"`
+	Reason        string    `json:"reason"`
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+func ValidateSalaryChange(v *validator.Validator, change *SalaryChange) {
+	ValidateSalary(v, "salary", change.NewSalary)
+	v.Check(!change.EffectiveDate.IsZero(), "salary_effective_date", "must be provided")
+	v.Check(len(change.Reason) <= 500, "salary_reason", "must not be more than 500 bytes long")
+}
+
+// SalaryAt returns the salary in effect on day, given the current salary and
+// the salary history ordered by effective date: the old salary of the first
+// change after day, or the current salary when there is none. Changes before
+// day don't matter, so the history may start at any earlier day.
+func SalaryAt(current Money, changes []*SalaryChange, day time.Time) Money {
+	for _, change := range changes {
+		if change.EffectiveDate.After(day) {
+			return change.OldSalary
+		}
+	}
+	return current
+}
+
+// ExperienceBands group years of experience for reporting, in ascending order.
+var ExperienceBands = []string{"0-2", "3-5", "6-10", "11+"}
+
+func ExperienceBand(years int) string {
+	switch {
+	case years <= 2:
+		return ExperienceBands[0]
+	case years <= 5:
+		return ExperienceBands[1]
+	case years <= 10:
+		return ExperienceBands[2]
+	default:
+		return ExperienceBands[3]
+	}
+}
+
+type PayrollRow struct {
+	Month          string `json:"month"`
+	Breed          string `json:"breed"`
+	ExperienceBand string `json:"experience_band"`
+	SpyCats        int    `json:"spy_cats"`
+	Cost           Money  `json:"cost"`
+}
+
+// PayrollReport has a row per currency of the salaries within a month, breed
+// and experience band, and a total per currency.
+type PayrollReport struct {
+	From   string       `json:"from"`
+	To     string       `json:"to"`
+	Rows   []PayrollRow `json:"rows"`
+	Totals []Money      `json:"totals"`
+}
+
+func ValidatePayrollRange(v *validator.Validator, from, to time.Time) {
+	v.Check(!from.IsZero(), "from", "must be provided")
+	v.Check(!to.IsZero(), "to", "must be provided")
+	if from.IsZero() || to.IsZero() {
+		return
+	}
+
+	v.Check(!to.Before(from), "to", "must not be before from")
+	v.Check(MonthsBetween(from, to) <= MaxPayrollMonths, "to", "must be at most 120 months after from")
+}
+
+// MonthsBetween returns the number of months from the month of from to the
+// month of to, both included.
+func MonthsBetween(from, to time.Time) int {
+	return (to.Year()-from.Year())*12 + int(to.Month()-from.Month()) + 1
+}
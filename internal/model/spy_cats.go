@@ -1,25 +1,46 @@
 package model
 
-import "github.com/m1crogravity/spy-cat-agency/internal/validator"
+import (
+	"strings"
+	"time"
+
+	"github.com/m1crogravity/spy-cat-agency/internal/validator"
+)
 
 var AnonymousSpyCat = &SpyCat{}
 
 const SpyCatUserType = UserType("spy-cat")
 
+// Archived spy cats are hidden from lists and can't log in, but are kept for
+// the history of their missions.
+const (
+	SpyCatStatusActive   = "active"
+	SpyCatStatusArchived = "archived"
+	SpyCatStatusAll      = "all"
+)
+
+var SpyCatStatuses = []string{SpyCatStatusActive, SpyCatStatusArchived, SpyCatStatusAll}
+
 type SpyCat struct {
-	Id                int64    `json:"id"`
-	Name              string   `json:"name"`
-	YearsOfExperience int      `json:"years_of_experience"`
-	Breed             string   `json:"breed"`
-	Salary            float64  `json:"salary"`
-	Password          Password `json:"-"`
+	Id                int64      `json:"id"`
+	Name              string     `json:"name"`
+	YearsOfExperience int        `json:"years_of_experience"`
+	Breed             string     `json:"breed"`
+	Salary            Money      `json:"salary"`
+	CreatedAt         time.Time  `json:"created_at"`
+	DeletedAt         *time.Time `json:"deleted_at,omitempty"`
+	Password          Password   `json:"-"`
 }
 
 func (sc *SpyCat) IsAnonymous() bool {
 	return sc == AnonymousSpyCat
 }
 
-func ValidateSpyCat(v *validator.Validator, spyCat *SpyCat, breeds []string) {
+func (sc *SpyCat) IsArchived() bool {
+	return sc.DeletedAt != nil
+}
+
+func ValidateSpyCat(v *validator.Validator, spyCat *SpyCat) {
 	v.Check(spyCat.Name != "", "name", "must be provided")
 	v.Check(len(spyCat.Name) <= 500, "name", "must be more than 500 bytes long")
 
@@ -31,5 +52,73 @@ func ValidateSpyCat(v *validator.Validator, spyCat *SpyCat, breeds []string) {
 		panic("missing password hash for user")
 	}
 
-	v.Check(validator.PermittedValue(spyCat.Breed, breeds...), "breed", "invalid breed")
+	ValidateSalary(v, "salary", spyCat.Salary)
+
+	v.Check(spyCat.Breed != "", "breed", "must be provided")
+}
+
+// ValidateBreed checks that breed is one of breeds and suggests the closest
+// ones when it isn't.
+func ValidateBreed(v *validator.Validator, breed string, breeds []Breed) {
+	if _, ok := FindBreed(breeds, breed); !ok {
+		message := "invalid breed"
+		if closest := ClosestBreedNames(breeds, breed, breedSuggestions); len(closest) > 0 {
+			message += ", did you mean: " + strings.Join(closest, ", ")
+		}
+		v.AddError("breed", message)
+	}
+}
+
+var SpyCatsSortSafelist = []string{
+	"id", "name", "years_of_experience", "salary",
+	"-id", "-name", "-years_of_experience", "-salary",
+}
+
+type SpyCatsFilter struct {
+	// Status is one of SpyCatStatuses, empty means active.
+	Status        string
+	Breed         string
+	MinExperience *int
+	MaxExperience *int
+	MinSalary     *Money
+	MaxSalary     *Money
+	Filters
+}
+
+func (f SpyCatsFilter) Matches(spyCat *SpyCat) bool {
+	switch {
+	case f.Status != SpyCatStatusAll && (f.Status == SpyCatStatusArchived) != spyCat.IsArchived():
+		return false
+	case f.Breed != "" && NormalizeBreed(spyCat.Breed) != NormalizeBreed(f.Breed):
+		return false
+	case f.MinExperience != nil && spyCat.YearsOfExperience < *f.MinExperience:
+		return false
+	case f.MaxExperience != nil && spyCat.YearsOfExperience > *f.MaxExperience:
+		return false
+	case f.MinSalary != nil && (spyCat.Salary.Currency != f.MinSalary.Currency || spyCat.Salary.Amount < f.MinSalary.Amount):
+		return false
+	case f.MaxSalary != nil && (spyCat.Salary.Currency != f.MaxSalary.Currency || spyCat.Salary.Amount > f.MaxSalary.Amount):
+		return false
+	}
+
+	return true
+}
+
+func ValidateSpyCatsFilter(v *validator.Validator, f SpyCatsFilter) {
+	ValidateFilters(v, f.Filters)
+
+	v.Check(validator.PermittedValue(f.Status, SpyCatStatuses...), "status", "invalid status value")
+
+	if f.MinExperience != nil {
+		v.Check(*f.MinExperience >= 0, "min_experience", "must not be negative")
+	}
+	if f.MaxExperience != nil {
+		v.Check(*f.MaxExperience >= 0, "max_experience", "must not be negative")
+	}
+	if f.MinExperience != nil && f.MaxExperience != nil {
+		v.Check(*f.MinExperience <= *f.MaxExperience, "max_experience", "must not be less than min_experience")
+	}
+	if f.MinSalary != nil && f.MaxSalary != nil {
+		v.Check(f.MinSalary.Amount <= f.MaxSalary.Amount, "max_salary", "must not be less than min_salary")
+	}
 }
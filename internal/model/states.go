@@ -0,0 +1,126 @@
+package model
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+)
+
+// ErrInvalidTransition is wrapped by every TransitionError.
+var ErrInvalidTransition = errors.New("invalid state transition")
+
+// MissionEvent is something done to a mission that moves it between states.
+type MissionEvent string
+
+const (
+	AssignMission   MissionEvent = "assign"
+	UnassignMission MissionEvent = "unassign"
+	HandOffMission  MissionEvent = "hand off"
+	CompleteMission MissionEvent = "complete"
+	AbortMission    MissionEvent = "abort"
+	FailMission     MissionEvent = "fail"
+	ReopenMission   MissionEvent = "reopen"
+)
+
+// TargetEvent is something done to a target that moves it between states.
+type TargetEvent string
+
+const (
+	StartTarget    TargetEvent = "start"
+	ResetTarget    TargetEvent = "reset"
+	CompleteTarget TargetEvent = "complete"
+	RedoTarget     TargetEvent = "redo"
+)
+
+type transition struct {
+	from []CompleteState
+	to   CompleteState
+}
+
+// missionTransitions is the mission state machine: the states each event may
+// be applied in and the state it leads to.
+var missionTransitions = map[MissionEvent]transition{
+	AssignMission:   {from: []CompleteState{Created}, to: InProgress},
+	UnassignMission: {from: []CompleteState{InProgress}, to: Created},
+	HandOffMission:  {from: []CompleteState{InProgress}, to: InProgress},
+	CompleteMission: {from: []CompleteState{InProgress}, to: Completed},
+	AbortMission:    {from: []CompleteState{Created, InProgress}, to: Aborted},
+	FailMission:     {from: []CompleteState{InProgress}, to: Failed},
+	ReopenMission:   {from: []CompleteState{Completed, Aborted, Failed}, to: Created},
+}
+
+// targetTransitions is the target state machine. Completed targets are final
+// unless their mission is reopened with nothing left to do.
+var targetTransitions = map[TargetEvent]transition{
+	StartTarget:    {from: []CompleteState{Created}, to: InProgress},
+	ResetTarget:    {from: []CompleteState{InProgress}, to: Created},
+	CompleteTarget: {from: []CompleteState{InProgress}, to: Completed},
+	RedoTarget:     {from: []CompleteState{Completed}, to: Created},
+}
+
+// TransitionError is returned when an event can't be applied in the current
+// state of a mission or a target.
+type TransitionError struct {
+	Subject string
+	Event   string
+	State   CompleteState
+}
+
+func (e *TransitionError) Error() string {
+	return fmt.Sprintf("can't %s the %s in the %s state", e.Event, e.Subject, e.State)
+}
+
+func (e *TransitionError) Unwrap() error {
+	return ErrInvalidTransition
+}
+
+// Apply moves the mission to the state the event leads to. Unfinished targets
+// follow the mission when it is assigned, unassigned or reopened. A mission
+// reopened with every target completed starts them all over, otherwise it
+// could never be completed again. A reason is kept only for aborted and
+// failed missions.
+func (m *Mission) Apply(event MissionEvent, reason string) error {
+	t := missionTransitions[event]
+	if !slices.Contains(t.from, m.State) {
+		return &TransitionError{Subject: "mission", Event: string(event), State: m.State}
+	}
+
+	m.State = t.to
+	m.Reason = ""
+	switch event {
+	case AssignMission:
+		m.applyTargets(StartTarget)
+	case UnassignMission:
+		m.AssignedCatId = 0
+		m.applyTargets(ResetTarget)
+	case ReopenMission:
+		m.AssignedCatId = 0
+		if m.IsAllTargetsComplete() {
+			m.applyTargets(RedoTarget)
+		}
+		m.applyTargets(ResetTarget)
+	case AbortMission, FailMission:
+		m.Reason = reason
+	}
+
+	return nil
+}
+
+func (m *Mission) applyTargets(event TargetEvent) {
+	for _, target := range m.Targets {
+		if slices.Contains(targetTransitions[event].from, target.State) {
+			target.State = targetTransitions[event].to
+		}
+	}
+}
+
+// Apply moves the target to the state the event leads to.
+func (t *Target) Apply(event TargetEvent) error {
+	tr := targetTransitions[event]
+	if !slices.Contains(tr.from, t.State) {
+		return &TransitionError{Subject: "target", Event: string(event), State: t.State}
+	}
+
+	t.State = tr.to
+	return nil
+}
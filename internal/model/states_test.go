@@ -0,0 +1,138 @@
+package model
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestMissionApply(t *testing.T) {
+	tests := []struct {
+		from  CompleteState
+		event MissionEvent
+		want  CompleteState
+		ok    bool
+	}{
+		{from: Created, event: AssignMission, want: InProgress, ok: true},
+		{from: Created, event: CompleteMission},
+		{from: Created, event: FailMission},
+		{from: Created, event: AbortMission, want: Aborted, ok: true},
+		{from: InProgress, event: AssignMission},
+		{from: InProgress, event: UnassignMission, want: Created, ok: true},
+		{from: InProgress, event: HandOffMission, want: InProgress, ok: true},
+		{from: InProgress, event: CompleteMission, want: Completed, ok: true},
+		{from: InProgress, event: AbortMission, want: Aborted, ok: true},
+		{from: InProgress, event: FailMission, want: Failed, ok: true},
+		{from: InProgress, event: ReopenMission},
+		{from: Completed, event: AbortMission},
+		{from: Completed, event: UnassignMission},
+		{from: Completed, event: ReopenMission, want: Created, ok: true},
+		{from: Aborted, event: AssignMission},
+		{from: Aborted, event: ReopenMission, want: Created, ok: true},
+		{from: Failed, event: CompleteMission},
+		{from: Failed, event: ReopenMission, want: Created, ok: true},
+	}
+
+	for _, tt := range tests {
+		mission := &Mission{State: tt.from}
+		err := mission.Apply(tt.event, "reason")
+		if !tt.ok {
+			var transitionErr *TransitionError
+			if !errors.As(err, &transitionErr) || !errors.Is(err, ErrInvalidTransition) {
+				t.Errorf("%s from %s: expected transition error, got %v", tt.event, tt.from, err)
+			}
+			if mission.State != tt.from {
+				t.Errorf("%s from %s: state changed to %s", tt.event, tt.from, mission.State)
+			}
+			continue
+		}
+		if err != nil || mission.State != tt.want {
+			t.Errorf("%s from %s = %s, %v, want %s", tt.event, tt.from, mission.State, err, tt.want)
+		}
+	}
+}
+
+func TestMissionApplyTargetsAndReason(t *testing.T) {
+	mission := &Mission{
+		AssignedCatId: 1,
+		State:         InProgress,
+		Targets: []*Target{
+			{State: Completed},
+			{State: InProgress, Notes: "spotted"},
+		},
+	}
+
+	err := mission.Apply(FailMission, "cover blown")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if mission.Reason != "cover blown" || mission.Targets[1].State != InProgress {
+		t.Fatalf("unexpected failed mission %+v", mission)
+	}
+
+	err = mission.Apply(ReopenMission, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if mission.Reason != "" || mission.IsAssignedToCat() {
+		t.Fatal("Expected reopened mission to drop the reason and the spy cat")
+	}
+	if mission.Targets[0].State != Completed {
+		t.Fatal("Expected completed target to stay completed")
+	}
+	if mission.Targets[1].State != Created || mission.Targets[1].Notes != "spotted" {
+		t.Fatal("Expected unfinished target to return to created with its notes")
+	}
+}
+
+func TestMissionReopenAllTargetsCompleted(t *testing.T) {
+	mission := &Mission{
+		AssignedCatId: 1,
+		State:         Completed,
+		Targets: []*Target{
+			{State: Completed, Notes: "spotted"},
+			{State: Completed},
+		},
+	}
+
+	err := mission.Apply(ReopenMission, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, target := range mission.Targets {
+		if target.State != Created {
+			t.Fatalf("Expected every target to start over, got %s", target.State)
+		}
+	}
+	if mission.Targets[0].Notes != "spotted" {
+		t.Fatal("Expected the target to keep its notes")
+	}
+
+	err = mission.Apply(AssignMission, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, target := range mission.Targets {
+		if err := target.Apply(CompleteTarget); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if !mission.IsAllTargetsComplete() {
+		t.Fatal("Expected the reopened mission to be completable again")
+	}
+}
+
+func TestTargetApply(t *testing.T) {
+	target := &Target{State: Created}
+	if err := target.Apply(CompleteTarget); !errors.Is(err, ErrInvalidTransition) {
+		t.Fatalf("Expected created target not to be completed, got %v", err)
+	}
+	if err := target.Apply(StartTarget); err != nil || target.State != InProgress {
+		t.Fatalf("unexpected target state %s, %v", target.State, err)
+	}
+	if err := target.Apply(CompleteTarget); err != nil || target.State != Completed {
+		t.Fatalf("unexpected target state %s, %v", target.State, err)
+	}
+	if err := target.Apply(ResetTarget); !errors.Is(err, ErrInvalidTransition) {
+		t.Fatalf("Expected completed target to be final, got %v", err)
+	}
+}
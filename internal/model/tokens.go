@@ -11,10 +11,16 @@ import (
 
 const (
 	ScopeAuthentication = "authentication"
+	ScopeInvitation     = "invitation"
+	ScopePasswordReset  = "password-reset"
 )
 
 type UserType string
 
+// InvitationUserType owns invitation tokens. Their UserID is the inviting
+// agent, but revoking that agent's tokens must not void the invitations.
+const InvitationUserType = UserType("invitation")
+
 type Token struct {
 	Plaintext string    `json:"token"`
 	Hash      []byte    `json:"-"`
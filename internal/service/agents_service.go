@@ -2,14 +2,20 @@ package service
 
 import (
 	"context"
+	"errors"
 
 	"github.com/m1crogravity/spy-cat-agency/internal/model"
 )
 
+var ErrAgentsExist = errors.New("agents already exist, bootstrap is only allowed on an empty agents table")
+
 type AgentsRepository interface {
 	FindByName(context.Context, string) (*model.Agent, error)
 	Create(context.Context, *model.Agent) error
 	FindById(context.Context, int64) (*model.Agent, error)
+	UpdateRole(context.Context, *model.Agent) error
+	CreateIfEmpty(context.Context, *model.Agent) (bool, error)
+	UpdatePassword(context.Context, *model.Agent) error
 }
 
 type AgentsService struct {
@@ -33,3 +39,34 @@ func (s *AgentsService) Create(ctx context.Context, agent *model.Agent) error {
 func (s *AgentsService) GetById(ctx context.Context, id int64) (*model.Agent, error) {
 	return s.repository.FindById(ctx, id)
 }
+
+func (s *AgentsService) ChangeRole(ctx context.Context, id int64, role model.Role) (*model.Agent, error) {
+	agent, err := s.repository.FindById(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	agent.Role = role
+
+	return agent, s.repository.UpdateRole(ctx, agent)
+}
+
+// Bootstrap creates the first agent as an admin. It fails with
+// ErrAgentsExist once any agent exists.
+func (s *AgentsService) Bootstrap(ctx context.Context, agent *model.Agent) error {
+	agent.Role = model.RoleAdmin
+
+	created, err := s.repository.CreateIfEmpty(ctx, agent)
+	if err != nil {
+		return err
+	}
+	if !created {
+		return ErrAgentsExist
+	}
+
+	return nil
+}
+
+func (s *AgentsService) UpdatePassword(ctx context.Context, agent *model.Agent) error {
+	return s.repository.UpdatePassword(ctx, agent)
+}
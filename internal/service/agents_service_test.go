@@ -0,0 +1,64 @@
+package service
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/m1crogravity/spy-cat-agency/internal/model"
+	"github.com/m1crogravity/spy-cat-agency/internal/storage/memory"
+)
+
+func TestAgentsBootstrap(t *testing.T) {
+	repo := memory.NewAgentsRepository()
+	service := NewAgentsService(repo)
+
+	agent := &model.Agent{
+		Name: "Head Agent",
+		Role: model.RoleAnalyst,
+	}
+	err := service.Bootstrap(t.Context(), agent)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if agent.Id == 0 {
+		t.Fatal("agent ID was not set")
+	}
+	if agent.Role != model.RoleAdmin {
+		t.Fatal("bootstrapped agent is not an admin")
+	}
+
+	err = service.Bootstrap(t.Context(), &model.Agent{Name: "Second Agent"})
+	if !errors.Is(err, ErrAgentsExist) {
+		t.Fatal("expected bootstrap to be refused")
+	}
+}
+
+func TestAgentsChangeRole(t *testing.T) {
+	repo := memory.NewAgentsRepository()
+	service := NewAgentsService(repo)
+
+	agent := &model.Agent{
+		Name: "Agent Smith",
+		Role: model.RoleAnalyst,
+	}
+	err := service.Create(t.Context(), agent)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = service.ChangeRole(t.Context(), agent.Id, model.RoleHandler)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := service.GetById(t.Context(), agent.Id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Role != model.RoleHandler {
+		t.Fatal("role was not changed")
+	}
+	if got.HasPermission(model.PermissionSpyCatsWrite) {
+		t.Fatal("handler should not be able to write spy cats")
+	}
+}
@@ -0,0 +1,50 @@
+package service
+
+import (
+	"context"
+
+	"github.com/m1crogravity/spy-cat-agency/internal/model"
+	"github.com/m1crogravity/spy-cat-agency/internal/storage"
+)
+
+type BreedsService struct {
+	repository BreedRepository
+}
+
+func NewBreedsService(repo BreedRepository) *BreedsService {
+	return &BreedsService{
+		repository: repo,
+	}
+}
+
+// GetAll returns the breeds whose name starts with namePrefix, ignoring case.
+func (s *BreedsService) GetAll(ctx context.Context, namePrefix string) ([]model.Breed, error) {
+	breeds, err := s.repository.FindAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	matching := make([]model.Breed, 0, len(breeds))
+	for _, breed := range breeds {
+		if breed.HasNamePrefix(namePrefix) {
+			matching = append(matching, breed)
+		}
+	}
+
+	return matching, nil
+}
+
+func (s *BreedsService) GetById(ctx context.Context, id string) (*model.Breed, error) {
+	breeds, err := s.repository.FindAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, breed := range breeds {
+		if breed.Id == id {
+			return &breed, nil
+		}
+	}
+
+	return nil, storage.ErrorModelNotFound
+}
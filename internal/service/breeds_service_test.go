@@ -0,0 +1,50 @@
+package service
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/m1crogravity/spy-cat-agency/internal/model"
+	"github.com/m1crogravity/spy-cat-agency/internal/storage"
+	"github.com/m1crogravity/spy-cat-agency/internal/storage/memory"
+)
+
+func TestBreedsGetAll(t *testing.T) {
+	repo := memory.NewBreedsRepository("Bengal", "Birman", "Siamese")
+	service := NewBreedsService(repo)
+
+	breeds, err := service.GetAll(t.Context(), "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(breeds) != 3 {
+		t.Fatalf("Expected 3 breeds, got %d", len(breeds))
+	}
+
+	breeds, err = service.GetAll(t.Context(), "b")
+	if err != nil {
+		t.Fatal(err)
+	}
+	names := model.BreedNames(breeds)
+	if len(names) != 2 || names[0] != "Bengal" || names[1] != "Birman" {
+		t.Fatalf("Expected breeds starting with b, got %v", names)
+	}
+}
+
+func TestBreedsGetById(t *testing.T) {
+	repo := memory.NewBreedsRepository("Bengal", "Siamese")
+	service := NewBreedsService(repo)
+
+	breed, err := service.GetById(t.Context(), "siamese")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if breed.Name != "Siamese" {
+		t.Fatalf("Expected Siamese, got %s", breed.Name)
+	}
+
+	_, err = service.GetById(t.Context(), "pokemon")
+	if !errors.Is(err, storage.ErrorModelNotFound) {
+		t.Fatal("Expected breed not to be found")
+	}
+}
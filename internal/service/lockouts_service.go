@@ -0,0 +1,112 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/m1crogravity/spy-cat-agency/internal/model"
+	"github.com/m1crogravity/spy-cat-agency/internal/storage"
+)
+
+type LockoutRepository interface {
+	FindAllLocked(context.Context, time.Time) ([]*model.Lockout, error)
+	RegisterAttempt(ctx context.Context, userType model.UserType, name string, now, resetBefore time.Time, lockFor func(int) time.Duration) (*model.Lockout, bool, error)
+	Delete(context.Context, model.UserType, string) error
+	DeleteStale(ctx context.Context, now, resetBefore time.Time, limit int) (int64, error)
+}
+
+// LockoutPolicy describes when failed logins lock a name. Once Threshold
+// consecutive failures are reached every further failure doubles the lockout,
+// starting from BaseDelay and capped at MaxDelay. Failures older than
+// ResetAfter are forgotten.
+type LockoutPolicy struct {
+	Threshold  int
+	BaseDelay  time.Duration
+	MaxDelay   time.Duration
+	ResetAfter time.Duration
+}
+
+func (p LockoutPolicy) delay(failures int) time.Duration {
+	if failures < p.Threshold {
+		return 0
+	}
+
+	delay := p.BaseDelay
+	for range failures - p.Threshold {
+		delay *= 2
+		if delay >= p.MaxDelay {
+			return p.MaxDelay
+		}
+	}
+
+	return min(delay, p.MaxDelay)
+}
+
+type LockoutService struct {
+	repository LockoutRepository
+	policy     LockoutPolicy
+}
+
+func NewLockoutService(repo LockoutRepository, policy LockoutPolicy) *LockoutService {
+	return &LockoutService{
+		repository: repo,
+		policy:     policy,
+	}
+}
+
+// Attempt registers a login attempt of the name before its password is
+// checked, so concurrent guesses can't get past the threshold. It returns how
+// long the name stays locked, or zero when the attempt may go on.
+func (s *LockoutService) Attempt(ctx context.Context, userType model.UserType, name string) (time.Duration, error) {
+	now := time.Now()
+	lockout, counted, err := s.repository.RegisterAttempt(ctx, userType, name, now, now.Add(-s.policy.ResetAfter), s.policy.delay)
+	if err != nil {
+		return 0, err
+	}
+	if counted {
+		return 0, nil
+	}
+
+	return lockout.LockedUntil.Sub(now), nil
+}
+
+func (s *LockoutService) RegisterSuccess(ctx context.Context, userType model.UserType, name string) error {
+	err := s.repository.Delete(ctx, userType, name)
+	if err != nil && !errors.Is(err, storage.ErrorModelNotFound) {
+		return err
+	}
+
+	return nil
+}
+
+func (s *LockoutService) GetAllLocked(ctx context.Context) ([]*model.Lockout, error) {
+	return s.repository.FindAllLocked(ctx, time.Now())
+}
+
+func (s *LockoutService) Clear(ctx context.Context, userType model.UserType, name string) error {
+	return s.repository.Delete(ctx, userType, name)
+}
+
+// PurgeStale deletes the lockouts that are no longer locked and whose
+// failures are already forgotten, including the ones of unknown names.
+func (s *LockoutService) PurgeStale(ctx context.Context, batchSize int) (int64, error) {
+	if batchSize < 1 {
+		return 0, ErrInvalidBatchSize
+	}
+
+	now := time.Now()
+	resetBefore := now.Add(-s.policy.ResetAfter)
+
+	var total int64
+	for {
+		deleted, err := s.repository.DeleteStale(ctx, now, resetBefore, batchSize)
+		total += deleted
+		if err != nil {
+			return total, err
+		}
+		if deleted < int64(batchSize) {
+			return total, nil
+		}
+	}
+}
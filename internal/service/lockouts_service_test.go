@@ -0,0 +1,166 @@
+package service
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/m1crogravity/spy-cat-agency/internal/model"
+	"github.com/m1crogravity/spy-cat-agency/internal/storage"
+	"github.com/m1crogravity/spy-cat-agency/internal/storage/memory"
+)
+
+func TestLockoutsAttempt(t *testing.T) {
+	repo := memory.NewLockoutsRepository()
+	service := NewLockoutService(repo, LockoutPolicy{
+		Threshold:  3,
+		BaseDelay:  time.Minute,
+		MaxDelay:   3 * time.Minute,
+		ResetAfter: time.Hour,
+	})
+
+	for i := range 3 {
+		wait, err := service.Attempt(t.Context(), model.AgentUserType, "Agent Smith")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if wait != 0 {
+			t.Fatalf("Expected attempt %d to be allowed, got lockout of %s", i+1, wait)
+		}
+	}
+
+	wait, err := service.Attempt(t.Context(), model.AgentUserType, "Agent Smith")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if wait > time.Minute || wait < time.Minute-time.Second {
+		t.Fatalf("Expected attempt to be rejected for %s, got %s", time.Minute, wait)
+	}
+
+	lockout, err := repo.Find(t.Context(), model.AgentUserType, "Agent Smith")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if lockout.Failures != 3 {
+		t.Fatalf("Expected rejected attempts not to be counted, got %d failures", lockout.Failures)
+	}
+
+	wait, err = service.Attempt(t.Context(), model.SpyCatUserType, "Agent Smith")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if wait != 0 {
+		t.Fatal("Expected lockouts to be tracked per user type")
+	}
+
+	lockouts, err := service.GetAllLocked(t.Context())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(lockouts) != 1 {
+		t.Fatalf("Expected 1 lockout, got %d", len(lockouts))
+	}
+
+	err = service.RegisterSuccess(t.Context(), model.AgentUserType, "Agent Smith")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	wait, err = service.Attempt(t.Context(), model.AgentUserType, "Agent Smith")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if wait != 0 {
+		t.Fatal("Expected lockout to be lifted after successful login")
+	}
+}
+
+func TestLockoutPolicyDelay(t *testing.T) {
+	policy := LockoutPolicy{
+		Threshold: 3,
+		BaseDelay: time.Minute,
+		MaxDelay:  3 * time.Minute,
+	}
+
+	expected := []time.Duration{0, 0, time.Minute, 2 * time.Minute, 3 * time.Minute, 3 * time.Minute}
+	for i, delay := range expected {
+		if got := policy.delay(i + 1); got != delay {
+			t.Fatalf("Expected lockout of %s after %d failures, got %s", delay, i+1, got)
+		}
+	}
+}
+
+func TestLockoutsClear(t *testing.T) {
+	repo := memory.NewLockoutsRepository()
+	service := NewLockoutService(repo, LockoutPolicy{
+		Threshold:  1,
+		BaseDelay:  time.Minute,
+		MaxDelay:   time.Hour,
+		ResetAfter: time.Hour,
+	})
+
+	_, err := service.Attempt(t.Context(), model.SpyCatUserType, "Pickachu")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = service.Clear(t.Context(), model.SpyCatUserType, "Pickachu")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = service.Clear(t.Context(), model.SpyCatUserType, "Pickachu")
+	if !errors.Is(err, storage.ErrorModelNotFound) {
+		t.Fatal("Expected error to be storage.ErrorModelNotFound")
+	}
+
+	wait, err := service.Attempt(t.Context(), model.SpyCatUserType, "Pickachu")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if wait != 0 {
+		t.Fatal("Expected lockout to be cleared")
+	}
+}
+
+func TestLockoutsPurgeStale(t *testing.T) {
+	repo := memory.NewLockoutsRepository()
+	service := NewLockoutService(repo, LockoutPolicy{
+		Threshold:  1,
+		BaseDelay:  time.Hour,
+		MaxDelay:   time.Hour,
+		ResetAfter: time.Hour,
+	})
+
+	now := time.Now()
+	for _, attempt := range []struct {
+		name    string
+		at      time.Time
+		lockFor time.Duration
+	}{
+		{name: "Forgotten", at: now.Add(-2 * time.Hour)},
+		{name: "Recent", at: now},
+		{name: "Locked", at: now.Add(-2 * time.Hour), lockFor: 3 * time.Hour},
+	} {
+		_, _, err := repo.RegisterAttempt(t.Context(), model.AgentUserType, attempt.name, attempt.at, attempt.at, func(int) time.Duration {
+			return attempt.lockFor
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	deleted, err := service.PurgeStale(t.Context(), 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if deleted != 1 {
+		t.Fatalf("Expected 1 stale lockout to be purged, got %d", deleted)
+	}
+	for name, kept := range map[string]bool{"Forgotten": false, "Recent": true, "Locked": true} {
+		_, err := repo.Find(t.Context(), model.AgentUserType, name)
+		if kept != (err == nil) {
+			t.Fatalf("Expected lockout of %s to be kept: %t, got %v", name, kept, err)
+		}
+	}
+}
@@ -31,11 +31,14 @@ type MissionsRepository interface {
 	CreateTarget(context.Context, *model.Mission, *model.Target) error
 	FindMissionById(context.Context, int64) (*model.Mission, error)
 	DeleteMission(context.Context, int64) error
-	SaveMission(context.Context, *model.Mission) error
+	SaveMission(context.Context, *model.Mission, model.CompleteState) error
 	SaveTarget(context.Context, *model.Target) error
 	DeleteTarget(context.Context, *model.Mission, int64) error
 	FindActiveMission(context.Context, int64) (*model.Mission, error)
-	FindAll(context.Context) ([]*model.Mission, error)
+	FindAll(context.Context, model.MissionsFilter) ([]*model.Mission, error)
+	CountByState(context.Context) (map[model.CompleteState]int, error)
+	SaveMissionHandoff(context.Context, *model.Mission, model.CompleteState, *model.MissionHandoff) error
+	FindMissionHandoffs(context.Context, int64) ([]*model.MissionHandoff, error)
 }
 
 type MissionsService struct {
@@ -57,6 +60,16 @@ func (s *MissionsService) CreateMission(ctx context.Context, mission *model.Miss
 		return ErrTooMuchTargets
 	}
 
+	// New missions enter the state machine as created.
+	if mission.State == "" {
+		mission.State = model.Created
+	}
+	for _, target := range mission.Targets {
+		if target.State == "" {
+			target.State = model.Created
+		}
+	}
+
 	return s.repository.CreateMission(ctx, mission)
 }
 
@@ -86,9 +99,45 @@ func (s *MissionsService) CompleteMission(ctx context.Context, id int64) (*model
 		return mission, nil
 	}
 
-	mission.Complete()
+	from := mission.State
+	err = mission.Apply(model.CompleteMission, "")
+	if err != nil {
+		return nil, err
+	}
+
+	return mission, s.repository.SaveMission(ctx, mission, from)
+}
+
+// AbortMission calls the mission off, the reason is required.
+func (s *MissionsService) AbortMission(ctx context.Context, id int64, reason string) (*model.Mission, error) {
+	return s.finishMission(ctx, id, model.AbortMission, reason)
+}
+
+// FailMission marks the mission as failed, the reason is required.
+func (s *MissionsService) FailMission(ctx context.Context, id int64, reason string) (*model.Mission, error) {
+	return s.finishMission(ctx, id, model.FailMission, reason)
+}
+
+// ReopenMission brings a completed, aborted or failed mission back to created.
+// The spy cat is released and unfinished targets return to created, or every
+// target does when all of them are completed.
+func (s *MissionsService) ReopenMission(ctx context.Context, id int64) (*model.Mission, error) {
+	return s.finishMission(ctx, id, model.ReopenMission, "")
+}
+
+func (s *MissionsService) finishMission(ctx context.Context, id int64, event model.MissionEvent, reason string) (*model.Mission, error) {
+	mission, err := s.repository.FindMissionById(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	from := mission.State
+	err = mission.Apply(event, reason)
+	if err != nil {
+		return nil, err
+	}
 
-	return mission, s.repository.SaveMission(ctx, mission)
+	return mission, s.repository.SaveMission(ctx, mission, from)
 }
 
 func (s *MissionsService) CompleteTarget(ctx context.Context, mission *model.Mission, targetId int64, spyCat *model.SpyCat) error {
@@ -104,10 +153,16 @@ func (s *MissionsService) CompleteTarget(ctx context.Context, mission *model.Mis
 	if target.IsCompleted() {
 		return nil
 	}
+	if mission.IsFinished() {
+		return ErrOperationNotAllowedOnCompleted
+	}
 
-	target.Complete()
+	err := target.Apply(model.CompleteTarget)
+	if err != nil {
+		return err
+	}
 
-	err := s.repository.SaveTarget(ctx, target)
+	err = s.repository.SaveTarget(ctx, target)
 	if err != nil {
 		return err
 	}
@@ -126,7 +181,7 @@ func (s *MissionsService) UpdateNotes(ctx context.Context, mission *model.Missio
 	if !mission.IsAssignedTo(spyCat) {
 		return ErrAccessDenied
 	}
-	if mission.IsCompleted() {
+	if mission.IsFinished() {
 		return ErrOperationNotAllowedOnCompleted
 	}
 
@@ -156,7 +211,7 @@ func (s *MissionsService) RemoveTarget(ctx context.Context, mission *model.Missi
 }
 
 func (s *MissionsService) AddTarget(ctx context.Context, mission *model.Mission, target *model.Target) error {
-	if mission.IsCompleted() {
+	if mission.IsFinished() {
 		return ErrOperationNotAllowedOnCompleted
 	}
 
@@ -180,14 +235,108 @@ func (s *MissionsService) AssignMission(ctx context.Context, mission *model.Miss
 		return err
 	}
 
+	from := mission.State
+	err = mission.Apply(model.AssignMission, "")
+	if err != nil {
+		return err
+	}
 	mission.AssignedCatId = spyCat.Id
-	mission.State = model.InProgress
-	for _, target := range mission.Targets {
-		target.State = model.InProgress
+
+	err = s.repository.SaveMission(ctx, mission, from)
+	if errors.Is(err, storage.ErrorUniqueConstraintViolation) {
+		return ErrSpyCatIsBusy
+	}
+	return err
+}
+
+// UnassignMission takes the mission back from its spy cat on behalf of the
+// agent. The mission returns to created and its targets keep their notes.
+func (s *MissionsService) UnassignMission(ctx context.Context, mission *model.Mission, agentId int64) error {
+	if !mission.IsAssignedToCat() && mission.State == model.Created {
+		return nil
 	}
-	return s.repository.SaveMission(ctx, mission)
+
+	handoff := &model.MissionHandoff{
+		MissionId:    mission.Id,
+		FromSpyCatId: mission.AssignedCatId,
+		AgentId:      agentId,
+	}
+	from := mission.State
+	err := mission.Apply(model.UnassignMission, "")
+	if err != nil {
+		return err
+	}
+
+	return s.repository.SaveMissionHandoff(ctx, mission, from, handoff)
+}
+
+// HandOffMission moves a mission in progress to another available spy cat on
+// behalf of the agent. Targets are kept as they are, so completed ones stay
+// frozen.
+func (s *MissionsService) HandOffMission(ctx context.Context, mission *model.Mission, spyCat *model.SpyCat, agentId int64) error {
+	if mission.IsAssignedTo(spyCat) && mission.State == model.InProgress {
+		return nil
+	}
+	from := mission.State
+	err := mission.Apply(model.HandOffMission, "")
+	if err != nil {
+		return err
+	}
+	_, err = s.repository.FindActiveMission(ctx, spyCat.Id)
+	switch {
+	case errors.Is(err, storage.ErrorModelNotFound):
+	case err == nil:
+		return ErrSpyCatIsBusy
+	default:
+		return err
+	}
+
+	handoff := &model.MissionHandoff{
+		MissionId:    mission.Id,
+		FromSpyCatId: mission.AssignedCatId,
+		ToSpyCatId:   spyCat.Id,
+		AgentId:      agentId,
+	}
+	mission.AssignedCatId = spyCat.Id
+
+	err = s.repository.SaveMissionHandoff(ctx, mission, from, handoff)
+	if errors.Is(err, storage.ErrorUniqueConstraintViolation) {
+		return ErrSpyCatIsBusy
+	}
+	return err
+}
+
+func (s *MissionsService) GetMissionHandoffs(ctx context.Context, id int64) ([]*model.MissionHandoff, error) {
+	_, err := s.repository.FindMissionById(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.repository.FindMissionHandoffs(ctx, id)
+}
+
+func (s *MissionsService) GetAll(ctx context.Context, filter model.MissionsFilter) ([]*model.Mission, model.CursorMetadata, error) {
+	limit := filter.Limit
+	filter.Limit = limit + 1
+
+	missions, err := s.repository.FindAll(ctx, filter)
+	if err != nil {
+		return nil, model.CursorMetadata{}, err
+	}
+
+	metadata := model.CursorMetadata{Limit: limit}
+	if len(missions) > limit {
+		missions = missions[:limit]
+		metadata.NextCursor = model.EncodeCursor(missions[limit-1].Id)
+	}
+
+	return missions, metadata, nil
+}
+
+func (s *MissionsService) GetActiveMission(ctx context.Context, spyCat *model.SpyCat) (*model.Mission, error) {
+	return s.repository.FindActiveMission(ctx, spyCat.Id)
 }
 
-func (s *MissionsService) GetAll(ctx context.Context) ([]*model.Mission, error) {
-	return s.repository.FindAll(ctx)
+func (s *MissionsService) CountByState(ctx context.Context) (map[model.CompleteState]int, error) {
+	return s.repository.CountByState(ctx)
 }
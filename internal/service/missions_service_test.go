@@ -1,6 +1,7 @@
 package service
 
 import (
+	"errors"
 	"testing"
 
 	"github.com/m1crogravity/spy-cat-agency/internal/model"
@@ -126,6 +127,19 @@ func TestCompleteMission(t *testing.T) {
 	}{
 		{
 			name: "happy path",
+			mission: &model.Mission{
+				AssignedCatId: 1,
+				State:         model.InProgress,
+				Targets: []*model.Target{
+					{
+						State: model.InProgress,
+					},
+				},
+			},
+			errCheck: nil,
+		},
+		{
+			name: "unassigned mission",
 			mission: &model.Mission{
 				State: model.Created,
 				Targets: []*model.Target{
@@ -134,7 +148,7 @@ func TestCompleteMission(t *testing.T) {
 					},
 				},
 			},
-			errCheck: nil,
+			errCheck: model.ErrInvalidTransition,
 		},
 	}
 	for _, tt := range tc {
@@ -146,7 +160,7 @@ func TestCompleteMission(t *testing.T) {
 				t.Fatal(err)
 			}
 			mission, err := service.CompleteMission(t.Context(), tt.mission.Id)
-			if err != tt.errCheck {
+			if !errors.Is(err, tt.errCheck) {
 				t.Fatal(err)
 			}
 			if err != nil {
@@ -159,6 +173,71 @@ func TestCompleteMission(t *testing.T) {
 	}
 }
 
+func TestAbortFailReopenMission(t *testing.T) {
+	repo := memory.NewMissionsRepository()
+	service := NewMissionsService(repo)
+	spyCat := &model.SpyCat{Id: 1}
+
+	mission := &model.Mission{Targets: []*model.Target{{Name: "target1"}}}
+	err := service.CreateMission(t.Context(), mission)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = service.FailMission(t.Context(), mission.Id, "cover blown")
+	if !errors.Is(err, model.ErrInvalidTransition) {
+		t.Fatalf("Expected created mission not to fail, got %v", err)
+	}
+
+	err = service.AssignMission(t.Context(), mission, spyCat)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	failed, err := service.FailMission(t.Context(), mission.Id, "cover blown")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if failed.State != model.Failed || failed.Reason != "cover blown" {
+		t.Fatalf("unexpected failed mission %+v", failed)
+	}
+
+	_, err = service.GetActiveMission(t.Context(), spyCat)
+	if !errors.Is(err, storage.ErrorModelNotFound) {
+		t.Fatal("Expected spy cat to be released by the failed mission")
+	}
+
+	_, err = service.AbortMission(t.Context(), mission.Id, "called off")
+	if !errors.Is(err, model.ErrInvalidTransition) {
+		t.Fatalf("Expected failed mission not to be aborted, got %v", err)
+	}
+
+	reopened, err := service.ReopenMission(t.Context(), mission.Id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if reopened.State != model.Created || reopened.IsAssignedToCat() {
+		t.Fatalf("unexpected reopened mission %+v", reopened)
+	}
+
+	aborted, err := service.AbortMission(t.Context(), mission.Id, "called off")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if aborted.State != model.Aborted || aborted.Reason != "called off" {
+		t.Fatalf("unexpected aborted mission %+v", aborted)
+	}
+
+	_, err = service.ReopenMission(t.Context(), mission.Id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = service.ReopenMission(t.Context(), mission.Id)
+	if !errors.Is(err, model.ErrInvalidTransition) {
+		t.Fatalf("Expected created mission not to be reopened, got %v", err)
+	}
+}
+
 func TestCompleteTarget(t *testing.T) {
 	tc := []struct {
 		name     string
@@ -170,6 +249,7 @@ func TestCompleteTarget(t *testing.T) {
 		{
 			name: "happy path",
 			mission: &model.Mission{
+				State: model.InProgress,
 				Targets: []*model.Target{
 					{
 						State: model.InProgress,
@@ -185,6 +265,7 @@ func TestCompleteTarget(t *testing.T) {
 		{
 			name: "completed target",
 			mission: &model.Mission{
+				State: model.InProgress,
 				Targets: []*model.Target{
 					{
 						State: model.Completed,
@@ -200,6 +281,7 @@ func TestCompleteTarget(t *testing.T) {
 		{
 			name: "target with no mission",
 			mission: &model.Mission{
+				State: model.InProgress,
 				Targets: []*model.Target{
 					{
 						State: model.InProgress,
@@ -216,6 +298,7 @@ func TestCompleteTarget(t *testing.T) {
 			name: "wrong spy cat",
 			mission: &model.Mission{
 				AssignedCatId: 1,
+				State:         model.InProgress,
 				Targets: []*model.Target{
 					{
 						State: model.InProgress,
@@ -230,6 +313,38 @@ func TestCompleteTarget(t *testing.T) {
 			},
 			errCheck: ErrAccessDenied,
 		},
+		{
+			name: "aborted mission",
+			mission: &model.Mission{
+				State: model.Aborted,
+				Targets: []*model.Target{
+					{
+						State: model.InProgress,
+					},
+				},
+			},
+			target: func(m *model.Mission) *model.Target {
+				return m.Targets[0]
+			},
+			spyCat:   &model.SpyCat{},
+			errCheck: ErrOperationNotAllowedOnCompleted,
+		},
+		{
+			name: "failed mission",
+			mission: &model.Mission{
+				State: model.Failed,
+				Targets: []*model.Target{
+					{
+						State: model.InProgress,
+					},
+				},
+			},
+			target: func(m *model.Mission) *model.Target {
+				return m.Targets[0]
+			},
+			spyCat:   &model.SpyCat{},
+			errCheck: ErrOperationNotAllowedOnCompleted,
+		},
 	}
 	for _, tt := range tc {
 		t.Run(tt.name, func(t *testing.T) {
@@ -654,3 +769,426 @@ func TestAssignMission(t *testing.T) {
 		})
 	}
 }
+
+func TestUnassignMission(t *testing.T) {
+	tc := []struct {
+		name     string
+		mission  *model.Mission
+		errCheck error
+		handoffs int
+	}{
+		{
+			name: "happy path",
+			mission: &model.Mission{
+				AssignedCatId: 1,
+				State:         model.InProgress,
+				Targets: []*model.Target{
+					{State: model.Completed, Notes: "done"},
+					{State: model.InProgress, Notes: "spotted"},
+				},
+			},
+			handoffs: 1,
+		},
+		{
+			name: "not assigned",
+			mission: &model.Mission{
+				State: model.Created,
+				Targets: []*model.Target{
+					{State: model.Created},
+				},
+			},
+		},
+		{
+			name: "completed",
+			mission: &model.Mission{
+				AssignedCatId: 1,
+				State:         model.Completed,
+				Targets: []*model.Target{
+					{State: model.Completed},
+				},
+			},
+			errCheck: model.ErrInvalidTransition,
+		},
+	}
+	for _, tt := range tc {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := memory.NewMissionsRepository()
+			service := NewMissionsService(repo)
+
+			err := service.CreateMission(t.Context(), tt.mission)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			err = service.UnassignMission(t.Context(), tt.mission, 7)
+			if !errors.Is(err, tt.errCheck) {
+				t.Fatal(err)
+			}
+
+			handoffs, err := service.GetMissionHandoffs(t.Context(), tt.mission.Id)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(handoffs) != tt.handoffs {
+				t.Fatalf("Expected %d handoffs, got %d", tt.handoffs, len(handoffs))
+			}
+			if tt.errCheck != nil {
+				return
+			}
+
+			if tt.mission.IsAssignedToCat() {
+				t.Error("mission is still assigned")
+			}
+			if tt.mission.State != model.Created {
+				t.Error("mission is not created")
+			}
+			if tt.handoffs == 0 {
+				return
+			}
+			if handoffs[0].FromSpyCatId != 1 || handoffs[0].ToSpyCatId != 0 || handoffs[0].AgentId != 7 {
+				t.Errorf("unexpected handoff %+v", handoffs[0])
+			}
+			completed, unfinished := tt.mission.Targets[0], tt.mission.Targets[1]
+			if !completed.IsCompleted() || completed.Notes != "done" {
+				t.Error("completed target has changed")
+			}
+			if unfinished.State != model.Created || unfinished.Notes != "spotted" {
+				t.Error("unfinished target is not created or lost its notes")
+			}
+		})
+	}
+}
+
+func TestHandOffMission(t *testing.T) {
+	tc := []struct {
+		name     string
+		missions []*model.Mission
+		spyCat   *model.SpyCat
+		errCheck error
+	}{
+		{
+			name: "happy path",
+			missions: []*model.Mission{
+				{
+					AssignedCatId: 1,
+					State:         model.InProgress,
+					Targets: []*model.Target{
+						{State: model.Completed},
+						{State: model.InProgress},
+					},
+				},
+			},
+			spyCat: &model.SpyCat{Id: 2},
+		},
+		{
+			name: "not in progress",
+			missions: []*model.Mission{
+				{
+					State: model.Created,
+					Targets: []*model.Target{
+						{State: model.Created},
+					},
+				},
+			},
+			spyCat:   &model.SpyCat{Id: 2},
+			errCheck: model.ErrInvalidTransition,
+		},
+		{
+			name: "completed",
+			missions: []*model.Mission{
+				{
+					AssignedCatId: 1,
+					State:         model.Completed,
+					Targets: []*model.Target{
+						{State: model.Completed},
+					},
+				},
+			},
+			spyCat:   &model.SpyCat{Id: 2},
+			errCheck: model.ErrInvalidTransition,
+		},
+		{
+			name: "spy cat is busy",
+			missions: []*model.Mission{
+				{
+					AssignedCatId: 1,
+					State:         model.InProgress,
+					Targets: []*model.Target{
+						{State: model.InProgress},
+					},
+				},
+				{
+					AssignedCatId: 2,
+					State:         model.InProgress,
+					Targets: []*model.Target{
+						{State: model.InProgress},
+					},
+				},
+			},
+			spyCat:   &model.SpyCat{Id: 2},
+			errCheck: ErrSpyCatIsBusy,
+		},
+	}
+	for _, tt := range tc {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := memory.NewMissionsRepository()
+			service := NewMissionsService(repo)
+
+			for _, mission := range tt.missions {
+				err := service.CreateMission(t.Context(), mission)
+				if err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			mission := tt.missions[0]
+			fromSpyCatId := mission.AssignedCatId
+			err := service.HandOffMission(t.Context(), mission, tt.spyCat, 7)
+			if !errors.Is(err, tt.errCheck) {
+				t.Fatal(err)
+			}
+			if err != nil {
+				return
+			}
+
+			if !mission.IsAssignedTo(tt.spyCat) {
+				t.Error("mission is not handed off")
+			}
+			if mission.State != model.InProgress {
+				t.Error("mission is not in progress")
+			}
+			if !mission.Targets[0].IsCompleted() {
+				t.Error("completed target has changed")
+			}
+
+			handoffs, err := service.GetMissionHandoffs(t.Context(), mission.Id)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(handoffs) != 1 {
+				t.Fatalf("Expected 1 handoff, got %d", len(handoffs))
+			}
+			if handoffs[0].FromSpyCatId != fromSpyCatId || handoffs[0].ToSpyCatId != tt.spyCat.Id || handoffs[0].AgentId != 7 {
+				t.Errorf("unexpected handoff %+v", handoffs[0])
+			}
+		})
+	}
+}
+
+func TestHandOffAbortedMission(t *testing.T) {
+	repo := memory.NewMissionsRepository()
+	service := NewMissionsService(repo)
+	mission := &model.Mission{
+		AssignedCatId: 1,
+		State:         model.InProgress,
+		Targets:       []*model.Target{{State: model.InProgress}},
+	}
+	err := repo.CreateMission(t.Context(), mission)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The agent handing off still sees the mission in progress.
+	stale := *mission
+	_, err = service.AbortMission(t.Context(), mission.Id, "called off")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = service.HandOffMission(t.Context(), &stale, &model.SpyCat{Id: 2}, 1)
+	if !errors.Is(err, storage.ErrorEditConflict) {
+		t.Fatalf("Expected error to be storage.ErrorEditConflict, got %v", err)
+	}
+
+	saved, err := service.GetMissionByID(t.Context(), mission.Id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if saved.State != model.Aborted || saved.AssignedCatId != 1 {
+		t.Fatalf("Expected the aborted mission to be kept, got %+v", saved)
+	}
+}
+
+func TestAssignMissionArchivedSpyCat(t *testing.T) {
+	tests := []struct {
+		name    string
+		mission *model.Mission
+		assign  func(service *MissionsService, mission *model.Mission, spyCat *model.SpyCat) error
+	}{
+		{
+			name: "assign",
+			mission: &model.Mission{
+				State:   model.Created,
+				Targets: []*model.Target{{State: model.Created}},
+			},
+			assign: func(service *MissionsService, mission *model.Mission, spyCat *model.SpyCat) error {
+				return service.AssignMission(t.Context(), mission, spyCat)
+			},
+		},
+		{
+			name: "hand off",
+			mission: &model.Mission{
+				State:         model.InProgress,
+				AssignedCatId: 1,
+				Targets:       []*model.Target{{State: model.InProgress}},
+			},
+			assign: func(service *MissionsService, mission *model.Mission, spyCat *model.SpyCat) error {
+				return service.HandOffMission(t.Context(), mission, spyCat, 1)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			spyCatsRepo := memory.NewSpyCatRepository()
+			repo := memory.NewMissionsRepository().WithSpyCats(spyCatsRepo)
+			service := NewMissionsService(repo)
+
+			spyCats := []*model.SpyCat{{Name: "Tom"}, {Name: "Felix"}}
+			for _, spyCat := range spyCats {
+				err := spyCatsRepo.Create(t.Context(), spyCat)
+				if err != nil {
+					t.Fatal(err)
+				}
+			}
+			mission := tt.mission
+			err := repo.CreateMission(t.Context(), mission)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			// The agent assigning still sees the spy cat on the books.
+			spyCat := *spyCats[1]
+			err = spyCatsRepo.Delete(t.Context(), spyCat.Id)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			err = tt.assign(service, mission, &spyCat)
+			if !errors.Is(err, storage.ErrorModelNotFound) {
+				t.Fatalf("Expected error to be storage.ErrorModelNotFound, got %v", err)
+			}
+		})
+	}
+}
+
+func TestMissionsGetAll(t *testing.T) {
+	tc := []struct {
+		name     string
+		filter   model.MissionsFilter
+		expected []int64
+		hasNext  bool
+	}{
+		{
+			name:     "first page",
+			filter:   model.MissionsFilter{Limit: 2},
+			expected: []int64{1, 2},
+			hasNext:  true,
+		},
+		{
+			name:     "last page",
+			filter:   model.MissionsFilter{After: 2, Limit: 2},
+			expected: []int64{3, 4},
+			hasNext:  false,
+		},
+		{
+			name:     "by state",
+			filter:   model.MissionsFilter{State: model.InProgress, Limit: 10},
+			expected: []int64{2, 4},
+			hasNext:  false,
+		},
+		{
+			name:     "by spy cat",
+			filter:   model.MissionsFilter{SpyCatId: 7, Limit: 10},
+			expected: []int64{2},
+			hasNext:  false,
+		},
+		{
+			name:     "by country",
+			filter:   model.MissionsFilter{Country: "UA", Limit: 10},
+			expected: []int64{1, 4},
+			hasNext:  false,
+		},
+	}
+	for _, tt := range tc {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := memory.NewMissionsRepository()
+			service := NewMissionsService(repo)
+			for _, mission := range []*model.Mission{
+				{State: model.Created, Targets: []*model.Target{{Country: "ua"}}},
+				{State: model.InProgress, AssignedCatId: 7, Targets: []*model.Target{{Country: "us"}}},
+				{State: model.Completed, AssignedCatId: 8, Targets: []*model.Target{{Country: "pl"}}},
+				{State: model.InProgress, AssignedCatId: 9, Targets: []*model.Target{{Country: "us"}, {Country: "UA"}}},
+			} {
+				err := service.CreateMission(t.Context(), mission)
+				if err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			missions, metadata, err := service.GetAll(t.Context(), tt.filter)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(missions) != len(tt.expected) {
+				t.Fatalf("expected %d missions, got %d", len(tt.expected), len(missions))
+			}
+			for i, mission := range missions {
+				if mission.Id != tt.expected[i] {
+					t.Fatalf("expected mission %d at position %d, got %d", tt.expected[i], i, mission.Id)
+				}
+			}
+			if (metadata.NextCursor != "") != tt.hasNext {
+				t.Fatalf("unexpected next cursor %q", metadata.NextCursor)
+			}
+			if metadata.NextCursor == "" {
+				return
+			}
+			after, err := model.DecodeCursor(metadata.NextCursor)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if after != tt.expected[len(tt.expected)-1] {
+				t.Fatalf("cursor points to %d", after)
+			}
+		})
+	}
+}
+
+func TestGetActiveMission(t *testing.T) {
+	repo := memory.NewMissionsRepository()
+	service := NewMissionsService(repo)
+
+	spyCat := &model.SpyCat{Id: 1}
+	_, err := service.GetActiveMission(t.Context(), spyCat)
+	if err != storage.ErrorModelNotFound {
+		t.Fatal("expected no active mission")
+	}
+
+	mission := &model.Mission{
+		Targets: []*model.Target{
+			{},
+		},
+	}
+	err = service.CreateMission(t.Context(), mission)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = service.AssignMission(t.Context(), mission, spyCat)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	active, err := service.GetActiveMission(t.Context(), spyCat)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if active.Id != mission.Id {
+		t.Fatal("wrong active mission")
+	}
+
+	_, err = service.GetActiveMission(t.Context(), &model.SpyCat{Id: 2})
+	if err != storage.ErrorModelNotFound {
+		t.Fatal("other spy cat has an active mission")
+	}
+}
@@ -1,33 +1,51 @@
 package service
 
 import (
+	"cmp"
 	"context"
+	"errors"
+	"log/slog"
+	"slices"
+	"strings"
+	"time"
 
 	"github.com/m1crogravity/spy-cat-agency/internal/model"
+	"github.com/m1crogravity/spy-cat-agency/internal/storage"
 )
 
+var ErrCantDeleteSpyCat = errors.New("can't delete the spy cat while it has a mission in progress")
+
+// payrollPageSize is the number of spy cats read at once for a payroll report.
+const payrollPageSize = 100
+
 type SpyCatsRepository interface {
 	Create(context.Context, *model.SpyCat) error
 	FindById(context.Context, int64) (*model.SpyCat, error)
-	FindAll(context.Context) ([]*model.SpyCat, error)
-	Save(context.Context, model.SpyCat) error
+	FindAll(context.Context, model.SpyCatsFilter) ([]*model.SpyCat, int, error)
+	Save(context.Context, model.SpyCat, *model.SalaryChange) error
 	Delete(context.Context, int64) error
+	Restore(context.Context, int64) error
 	FindByName(context.Context, string) (*model.SpyCat, error)
+	UpdatePassword(context.Context, *model.SpyCat) error
+	FindSalaryChanges(context.Context, int64) ([]*model.SalaryChange, error)
+	FindSalaryChangesBetween(ctx context.Context, after, until time.Time) ([]*model.SalaryChange, error)
 }
 
 type BreedRepository interface {
-	FindAll(context.Context) ([]string, error)
+	FindAll(context.Context) ([]model.Breed, error)
 }
 
 type SpyCatService struct {
 	repository      SpyCatsRepository
 	breedRepository BreedRepository
+	logger          *slog.Logger
 }
 
-func NewSpyCatService(repo SpyCatsRepository, breedRepo BreedRepository) *SpyCatService {
+func NewSpyCatService(repo SpyCatsRepository, breedRepo BreedRepository, logger *slog.Logger) *SpyCatService {
 	return &SpyCatService{
 		repository:      repo,
 		breedRepository: breedRepo,
+		logger:          logger,
 	}
 }
 
@@ -35,33 +53,225 @@ func (s *SpyCatService) Create(ctx context.Context, spyCat *model.SpyCat) error
 	return s.repository.Create(ctx, spyCat)
 }
 
-func (s *SpyCatService) UpdateSalary(ctx context.Context, id int64, salary float64) (*model.SpyCat, error) {
-	spyCat, err := s.repository.FindById(ctx, id)
+// UpdateSalary sets the salary of a spy cat to the new salary of change and
+// records the change in the salary history.
+func (s *SpyCatService) UpdateSalary(ctx context.Context, id int64, change *model.SalaryChange) (*model.SpyCat, error) {
+	spyCat, err := s.GetById(ctx, id)
 	if err != nil {
 		return nil, err
 	}
 
-	spyCat.Salary = salary
+	change.SpyCatId = spyCat.Id
+	change.OldSalary = spyCat.Salary
+	spyCat.Salary = change.NewSalary
+
+	return spyCat, s.repository.Save(ctx, *spyCat, change)
+}
+
+// Update saves the spy cat. A salary change, if any, is recorded in the
+// salary history in the same transaction. When the salary was changed since
+// the spy cat was read, storage.ErrorEditConflict is returned.
+func (s *SpyCatService) Update(ctx context.Context, spyCat *model.SpyCat, change *model.SalaryChange) error {
+	return s.repository.Save(ctx, *spyCat, change)
+}
+
+func (s *SpyCatService) GetSalaryHistory(ctx context.Context, id int64) ([]*model.SalaryChange, error) {
+	_, err := s.repository.FindById(ctx, id)
+	if err != nil {
+		return nil, err
+	}
 
-	return spyCat, s.repository.Save(ctx, *spyCat)
+	return s.repository.FindSalaryChanges(ctx, id)
 }
 
-func (s *SpyCatService) GetAll(ctx context.Context) ([]*model.SpyCat, error) {
-	return s.repository.FindAll(ctx)
+// GetPayrollReport totals the monthly salary cost per breed, experience band
+// and currency for every month from the month of from to the month of to. A
+// month is charged a twelfth of the salary in effect on its first day, as the
+// API documents salaries as annual. Spy cats are charged for every month they
+// were on the books in, from the month they were created in to the month they
+// were archived in. They are grouped by their current breed and experience
+// band, neither is tracked over time.
+func (s *SpyCatService) GetPayrollReport(ctx context.Context, from, to time.Time) (*model.PayrollReport, error) {
+	from = time.Date(from.Year(), from.Month(), 1, 0, 0, 0, 0, time.UTC)
+	to = time.Date(to.Year(), to.Month(), 1, 0, 0, 0, 0, time.UTC)
+
+	// Only the changes after from and the first one after to decide the
+	// salaries within the range.
+	changes, err := s.repository.FindSalaryChangesBetween(ctx, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	history := make(map[int64][]*model.SalaryChange)
+	for _, change := range changes {
+		history[change.SpyCatId] = append(history[change.SpyCatId], change)
+	}
+
+	type payrollKey struct {
+		month          string
+		breed          string
+		experienceBand string
+		currency       string
+	}
+	rows := make(map[payrollKey]*model.PayrollRow)
+
+	filter := model.SpyCatsFilter{
+		Status:  model.SpyCatStatusAll,
+		Filters: model.Filters{Page: 1, PageSize: payrollPageSize, Sort: "id"},
+	}
+	for {
+		spyCats, totalRecords, err := s.repository.FindAll(ctx, filter)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, spyCat := range spyCats {
+			band := model.ExperienceBand(spyCat.YearsOfExperience)
+			for month := from; !month.After(to); month = month.AddDate(0, 1, 0) {
+				if !month.AddDate(0, 1, 0).After(spyCat.CreatedAt) {
+					continue
+				}
+				if spyCat.IsArchived() && !month.Before(*spyCat.DeletedAt) {
+					break
+				}
+				salary := model.SalaryAt(spyCat.Salary, history[spyCat.Id], month)
+				key := payrollKey{month.Format(model.MonthLayout), spyCat.Breed, band, salary.Currency}
+				row, ok := rows[key]
+				if !ok {
+					row = &model.PayrollRow{
+						Month:          key.month,
+						Breed:          key.breed,
+						ExperienceBand: key.experienceBand,
+						Cost:           model.Money{Currency: key.currency},
+					}
+					rows[key] = row
+				}
+				row.SpyCats++
+				// Annual salaries are summed up first and divided once, so
+				// that rounding doesn't add up over the spy cats.
+				row.Cost.Amount += salary.Amount
+			}
+		}
+
+		if len(spyCats) == 0 || filter.Offset()+len(spyCats) >= totalRecords {
+			break
+		}
+		filter.Page++
+	}
+
+	report := &model.PayrollReport{
+		From:   from.Format(model.MonthLayout),
+		To:     to.Format(model.MonthLayout),
+		Rows:   make([]model.PayrollRow, 0, len(rows)),
+		Totals: make([]model.Money, 0),
+	}
+	totals := make(map[string]int64)
+	for _, row := range rows {
+		row.Cost.Amount = (row.Cost.Amount + 6) / 12
+		totals[row.Cost.Currency] += row.Cost.Amount
+		report.Rows = append(report.Rows, *row)
+	}
+	for currency, amount := range totals {
+		report.Totals = append(report.Totals, model.Money{Amount: amount, Currency: currency})
+	}
+
+	slices.SortFunc(report.Rows, func(a, b model.PayrollRow) int {
+		return cmp.Or(
+			strings.Compare(a.Month, b.Month),
+			strings.Compare(a.Breed, b.Breed),
+			cmp.Compare(slices.Index(model.ExperienceBands, a.ExperienceBand), slices.Index(model.ExperienceBands, b.ExperienceBand)),
+			strings.Compare(a.Cost.Currency, b.Cost.Currency),
+		)
+	})
+	slices.SortFunc(report.Totals, func(a, b model.Money) int {
+		return strings.Compare(a.Currency, b.Currency)
+	})
+
+	return report, nil
 }
 
+func (s *SpyCatService) GetAll(ctx context.Context, filter model.SpyCatsFilter) ([]*model.SpyCat, model.Metadata, error) {
+	// Breeds are stored under their canonical name, so the filter is resolved
+	// the same way writes are, e.g. "rblu" or "russian  blue" to "Russian Blue".
+	// Listing doesn't depend on the breeds source, when it is down the filter
+	// matches the breed name as given.
+	if filter.Breed != "" {
+		breeds, err := s.breedRepository.FindAll(ctx)
+		if err != nil {
+			s.logger.WarnContext(ctx, "breeds unavailable, filtering by the breed as given", "error", err)
+			filter.Breed = model.NormalizeBreed(filter.Breed)
+		} else if breed, ok := model.FindBreed(breeds, filter.Breed); ok {
+			filter.Breed = breed.Name
+		}
+	}
+
+	spyCats, totalRecords, err := s.repository.FindAll(ctx, filter)
+	if err != nil {
+		return nil, model.Metadata{}, err
+	}
+
+	return spyCats, model.CalculateMetadata(totalRecords, filter.Page, filter.PageSize), nil
+}
+
+// GetById returns an active spy cat, archived ones are not found.
 func (s *SpyCatService) GetById(ctx context.Context, id int64) (*model.SpyCat, error) {
-	return s.repository.FindById(ctx, id)
+	spyCat, err := s.repository.FindById(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if spyCat.IsArchived() {
+		return nil, storage.ErrorModelNotFound
+	}
+
+	return spyCat, nil
 }
 
+// Remove archives a spy cat. A spy cat with a mission in progress can't be
+// removed and ErrCantDeleteSpyCat is returned.
 func (s *SpyCatService) Remove(ctx context.Context, id int64) error {
-	return s.repository.Delete(ctx, id)
+	err := s.repository.Delete(ctx, id)
+	if errors.Is(err, storage.ErrorEditConflict) {
+		return ErrCantDeleteSpyCat
+	}
+	return err
 }
 
+// Restore brings an archived spy cat back.
+func (s *SpyCatService) Restore(ctx context.Context, id int64) (*model.SpyCat, error) {
+	spyCat, err := s.repository.FindById(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if !spyCat.IsArchived() {
+		return spyCat, nil
+	}
+
+	err = s.repository.Restore(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	spyCat.DeletedAt = nil
+	return spyCat, nil
+}
+
+// GetByName returns an active spy cat, archived ones are not found.
 func (s *SpyCatService) GetByName(ctx context.Context, name string) (*model.SpyCat, error) {
-	return s.repository.FindByName(ctx, name)
+	spyCat, err := s.repository.FindByName(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	if spyCat.IsArchived() {
+		return nil, storage.ErrorModelNotFound
+	}
+
+	return spyCat, nil
 }
 
-func (s *SpyCatService) GetBreeds(ctx context.Context) ([]string, error) {
+func (s *SpyCatService) GetBreeds(ctx context.Context) ([]model.Breed, error) {
 	return s.breedRepository.FindAll(ctx)
 }
+
+func (s *SpyCatService) UpdatePassword(ctx context.Context, spyCat *model.SpyCat) error {
+	return s.repository.UpdatePassword(ctx, spyCat)
+}
@@ -1,23 +1,42 @@
 package service
 
 import (
+	"context"
 	"errors"
+	"io"
+	"log/slog"
+	"slices"
 	"testing"
+	"time"
 
 	"github.com/m1crogravity/spy-cat-agency/internal/model"
 	"github.com/m1crogravity/spy-cat-agency/internal/storage"
 	"github.com/m1crogravity/spy-cat-agency/internal/storage/memory"
+	"github.com/m1crogravity/spy-cat-agency/internal/validator"
 )
 
+var discardLogger = slog.New(slog.NewTextHandler(io.Discard, nil))
+
+// unavailableBreeds is a breeds source that is down.
+type unavailableBreeds struct{}
+
+func (unavailableBreeds) FindAll(context.Context) ([]model.Breed, error) {
+	return nil, errors.New("breeds API unavailable")
+}
+
+func usd(dollars int64) model.Money {
+	return model.Money{Amount: dollars * 100, Currency: "USD"}
+}
+
 func TestSpyCatsCreate(t *testing.T) {
 	repo := memory.NewSpyCatRepository()
 	breedRepo := memory.NewBreedsRepository("breed1", "pokemon")
-	service := NewSpyCatService(repo, breedRepo)
+	service := NewSpyCatService(repo, breedRepo, discardLogger)
 	spyCat := &model.SpyCat{
 		Name:              "Pickachu",
 		YearsOfExperience: 2,
 		Breed:             "pokemon",
-		Salary:            100,
+		Salary:            usd(100),
 	}
 	err := service.Create(t.Context(), spyCat)
 	if err != nil {
@@ -35,12 +54,12 @@ func TestSpyCatsCreate(t *testing.T) {
 func TestSpyCatsGetById(t *testing.T) {
 	repo := memory.NewSpyCatRepository()
 	breedRepo := memory.NewBreedsRepository("breed1", "pokemon")
-	service := NewSpyCatService(repo, breedRepo)
+	service := NewSpyCatService(repo, breedRepo, discardLogger)
 	spyCat := &model.SpyCat{
 		Name:              "Pickachu",
 		YearsOfExperience: 2,
 		Breed:             "pokemon",
-		Salary:            100,
+		Salary:            usd(100),
 	}
 	err := service.Create(t.Context(), spyCat)
 	if err != nil {
@@ -62,12 +81,12 @@ func TestSpyCatsGetById(t *testing.T) {
 func TestSpyCatsRemove(t *testing.T) {
 	repo := memory.NewSpyCatRepository()
 	breedRepo := memory.NewBreedsRepository("breed1", "pokemon")
-	service := NewSpyCatService(repo, breedRepo)
+	service := NewSpyCatService(repo, breedRepo, discardLogger)
 	spyCat := &model.SpyCat{
 		Name:              "Pickachu",
 		YearsOfExperience: 2,
 		Breed:             "pokemon",
-		Salary:            100,
+		Salary:            usd(100),
 	}
 	err := service.Create(t.Context(), spyCat)
 	if err != nil {
@@ -85,23 +104,146 @@ func TestSpyCatsRemove(t *testing.T) {
 	}
 }
 
+func TestSpyCatsRemoveBusy(t *testing.T) {
+	missionsRepo := memory.NewMissionsRepository()
+	repo := memory.NewSpyCatRepository().WithMissions(missionsRepo)
+	breedRepo := memory.NewBreedsRepository("pokemon")
+	service := NewSpyCatService(repo, breedRepo, discardLogger)
+	spyCat := &model.SpyCat{
+		Name:              "Pickachu",
+		YearsOfExperience: 2,
+		Breed:             "pokemon",
+		Salary:            usd(100),
+	}
+	err := service.Create(t.Context(), spyCat)
+	if err != nil {
+		t.Fatal(err)
+	}
+	mission := &model.Mission{State: model.InProgress, AssignedCatId: spyCat.Id}
+	err = missionsRepo.CreateMission(t.Context(), mission)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = service.Remove(t.Context(), spyCat.Id)
+	if !errors.Is(err, ErrCantDeleteSpyCat) {
+		t.Fatalf("Expected error to be ErrCantDeleteSpyCat, got %v", err)
+	}
+	_, err = service.GetById(t.Context(), spyCat.Id)
+	if err != nil {
+		t.Fatal("Expected the busy spy cat to stay active")
+	}
+
+	mission.State = model.Completed
+	err = service.Remove(t.Context(), spyCat.Id)
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestSpyCatsRestore(t *testing.T) {
+	repo := memory.NewSpyCatRepository()
+	breedRepo := memory.NewBreedsRepository("breed1", "pokemon")
+	service := NewSpyCatService(repo, breedRepo, discardLogger)
+	spyCat := &model.SpyCat{
+		Name:              "Pickachu",
+		YearsOfExperience: 2,
+		Breed:             "pokemon",
+		Salary:            usd(100),
+	}
+	err := service.Create(t.Context(), spyCat)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = service.Remove(t.Context(), spyCat.Id)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = service.GetById(t.Context(), spyCat.Id)
+	if !errors.Is(err, storage.ErrorModelNotFound) {
+		t.Fatal("Expected archived spy cat not to be found by ID")
+	}
+	_, err = service.GetByName(t.Context(), spyCat.Name)
+	if !errors.Is(err, storage.ErrorModelNotFound) {
+		t.Fatal("Expected archived spy cat not to be found by name")
+	}
+
+	filter := model.SpyCatsFilter{Filters: model.Filters{Page: 1, PageSize: 20, Sort: "id"}}
+	spyCats, _, err := service.GetAll(t.Context(), filter)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(spyCats) != 0 {
+		t.Fatal("Expected archived spy cat to be excluded from the list")
+	}
+
+	filter.Status = model.SpyCatStatusArchived
+	spyCats, _, err = service.GetAll(t.Context(), filter)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(spyCats) != 1 || !spyCats[0].IsArchived() {
+		t.Fatal("Expected archived spy cat to be listed with the archived status")
+	}
+
+	history, err := service.GetSalaryHistory(t.Context(), spyCat.Id)
+	if err != nil || len(history) != 0 {
+		t.Fatal("Expected salary history of archived spy cat to be kept")
+	}
+
+	restored, err := service.Restore(t.Context(), spyCat.Id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if restored.IsArchived() {
+		t.Fatal("Expected restored spy cat to be active")
+	}
+
+	_, err = service.GetById(t.Context(), spyCat.Id)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = service.Restore(t.Context(), spyCat.Id)
+	if err != nil {
+		t.Fatal("Expected restoring an active spy cat to be a no-op")
+	}
+
+	_, err = service.Restore(t.Context(), spyCat.Id+1)
+	if !errors.Is(err, storage.ErrorModelNotFound) {
+		t.Fatal("Expected error to be storage.ErrorModelNotFound")
+	}
+}
+
 func TestSpyCatsUpdateSalary(t *testing.T) {
 	repo := memory.NewSpyCatRepository()
 	breedRepo := memory.NewBreedsRepository("breed1", "pokemon")
-	service := NewSpyCatService(repo, breedRepo)
+	service := NewSpyCatService(repo, breedRepo, discardLogger)
 	spyCat := &model.SpyCat{
 		Name:              "Pickachu",
 		YearsOfExperience: 2,
 		Breed:             "pokemon",
-		Salary:            100,
+		Salary:            usd(100),
 	}
 	err := service.Create(t.Context(), spyCat)
 	if err != nil {
 		t.Fatal(err)
 	}
 
-	newSalary := 200.0
-	_, err = service.UpdateSalary(t.Context(), spyCat.Id, newSalary)
+	stale, err := service.GetById(t.Context(), spyCat.Id)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	newSalary := usd(200)
+	_, err = service.UpdateSalary(t.Context(), spyCat.Id, &model.SalaryChange{
+		AgentId:       1,
+		NewSalary:     newSalary,
+		EffectiveDate: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+		Reason:        "promotion",
+	})
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -112,17 +254,189 @@ func TestSpyCatsUpdateSalary(t *testing.T) {
 	if updatedSpyCat.Salary != newSalary {
 		t.Fatal("salary was not updated")
 	}
+
+	history, err := service.GetSalaryHistory(t.Context(), spyCat.Id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(history) != 1 || history[0].OldSalary != usd(100) || history[0].NewSalary != newSalary || history[0].AgentId != 1 {
+		t.Fatalf("unexpected salary history %+v", history)
+	}
+
+	_, err = service.UpdateSalary(t.Context(), spyCat.Id, &model.SalaryChange{
+		AgentId:       1,
+		NewSalary:     usd(300),
+		EffectiveDate: time.Date(2023, 12, 31, 0, 0, 0, 0, time.UTC),
+	})
+	if !errors.Is(err, model.ErrBackdatedSalaryChange) {
+		t.Fatal("Expected error to be model.ErrBackdatedSalaryChange")
+	}
+	if updatedSpyCat, err = service.GetById(t.Context(), spyCat.Id); err != nil || updatedSpyCat.Salary != newSalary {
+		t.Fatal("backdated salary change was saved")
+	}
+
+	// A spy cat read before the salary change must not overwrite it.
+	stale.Name = "Pichu"
+	err = service.Update(t.Context(), stale, nil)
+	if !errors.Is(err, storage.ErrorEditConflict) {
+		t.Fatal("Expected error to be storage.ErrorEditConflict")
+	}
+	err = service.Update(t.Context(), stale, &model.SalaryChange{
+		SpyCatId:      stale.Id,
+		AgentId:       1,
+		OldSalary:     stale.Salary,
+		NewSalary:     usd(300),
+		EffectiveDate: time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC),
+	})
+	if !errors.Is(err, storage.ErrorEditConflict) {
+		t.Fatal("Expected error to be storage.ErrorEditConflict")
+	}
+
+	_, err = service.GetSalaryHistory(t.Context(), spyCat.Id+1)
+	if !errors.Is(err, storage.ErrorModelNotFound) {
+		t.Fatal("Expected error to be storage.ErrorModelNotFound")
+	}
+}
+
+func TestSpyCatsGetPayrollReport(t *testing.T) {
+	repo := memory.NewSpyCatRepository()
+	breedRepo := memory.NewBreedsRepository("Bengal", "Siamese")
+	service := NewSpyCatService(repo, breedRepo, discardLogger)
+	spyCats := []*model.SpyCat{
+		{Name: "Whiskers", YearsOfExperience: 1, Breed: "Bengal", Salary: usd(12000), CreatedAt: time.Date(2023, 6, 1, 0, 0, 0, 0, time.UTC)},
+		{Name: "Shadow", YearsOfExperience: 2, Breed: "Bengal", Salary: usd(24000), CreatedAt: time.Date(2024, 2, 10, 0, 0, 0, 0, time.UTC)},
+		{Name: "Tom", YearsOfExperience: 7, Breed: "Siamese", Salary: usd(36000), CreatedAt: time.Date(2023, 6, 1, 0, 0, 0, 0, time.UTC)},
+		{Name: "Felix", YearsOfExperience: 3, Breed: "Siamese", Salary: usd(60000), CreatedAt: time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)},
+	}
+	for _, spyCat := range spyCats {
+		err := service.Create(t.Context(), spyCat)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	_, err := service.UpdateSalary(t.Context(), spyCats[2].Id, &model.SalaryChange{
+		NewSalary:     usd(48000),
+		EffectiveDate: time.Date(2024, 2, 15, 0, 0, 0, 0, time.UTC),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// A raise after the range doesn't change the salaries within it.
+	_, err = service.UpdateSalary(t.Context(), spyCats[0].Id, &model.SalaryChange{
+		NewSalary:     usd(18000),
+		EffectiveDate: time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	report, err := service.GetPayrollReport(t.Context(),
+		time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []model.PayrollRow{
+		{Month: "2024-01", Breed: "Bengal", ExperienceBand: "0-2", SpyCats: 1, Cost: usd(1000)},
+		{Month: "2024-01", Breed: "Siamese", ExperienceBand: "6-10", SpyCats: 1, Cost: usd(3000)},
+		{Month: "2024-02", Breed: "Bengal", ExperienceBand: "0-2", SpyCats: 2, Cost: usd(3000)},
+		{Month: "2024-02", Breed: "Siamese", ExperienceBand: "6-10", SpyCats: 1, Cost: usd(3000)},
+		{Month: "2024-03", Breed: "Bengal", ExperienceBand: "0-2", SpyCats: 2, Cost: usd(3000)},
+		{Month: "2024-03", Breed: "Siamese", ExperienceBand: "6-10", SpyCats: 1, Cost: usd(4000)},
+	}
+	if !slices.Equal(report.Rows, want) {
+		t.Fatalf("unexpected payroll rows %+v", report.Rows)
+	}
+	if !slices.Equal(report.Totals, []model.Money{usd(17000)}) {
+		t.Fatalf("Expected total of 17000 USD, got %v", report.Totals)
+	}
+}
+
+func TestSpyCatsGetPayrollReportArchived(t *testing.T) {
+	repo := memory.NewSpyCatRepository()
+	breedRepo := memory.NewBreedsRepository("Bengal")
+	service := NewSpyCatService(repo, breedRepo, discardLogger)
+	midMarch := time.Date(2024, 3, 15, 12, 0, 0, 0, time.UTC)
+	firstOfMarch := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	for _, spyCat := range []*model.SpyCat{
+		{Name: "Whiskers", Breed: "Bengal", Salary: usd(12000), CreatedAt: time.Date(2023, 6, 1, 0, 0, 0, 0, time.UTC), DeletedAt: &midMarch},
+		{Name: "Shadow", Breed: "Bengal", Salary: usd(24000), CreatedAt: time.Date(2023, 6, 1, 0, 0, 0, 0, time.UTC), DeletedAt: &firstOfMarch},
+	} {
+		err := service.Create(t.Context(), spyCat)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	report, err := service.GetPayrollReport(t.Context(),
+		time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC), time.Date(2024, 4, 1, 0, 0, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Whiskers is still charged for March, the month it was archived in.
+	// Shadow was archived as March began, so it isn't.
+	want := []model.PayrollRow{
+		{Month: "2024-02", Breed: "Bengal", ExperienceBand: "0-2", SpyCats: 2, Cost: usd(3000)},
+		{Month: "2024-03", Breed: "Bengal", ExperienceBand: "0-2", SpyCats: 1, Cost: usd(1000)},
+	}
+	if !slices.Equal(report.Rows, want) {
+		t.Fatalf("unexpected payroll rows %+v", report.Rows)
+	}
+}
+
+func TestSpyCatsUpdate(t *testing.T) {
+	repo := memory.NewSpyCatRepository()
+	breedRepo := memory.NewBreedsRepository("breed1", "pokemon")
+	service := NewSpyCatService(repo, breedRepo, discardLogger)
+	for _, name := range []string{"Pickachu", "Raichu"} {
+		err := service.Create(t.Context(), &model.SpyCat{Name: name, Breed: "pokemon"})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	spyCat, err := service.GetByName(t.Context(), "Pickachu")
+	if err != nil {
+		t.Fatal(err)
+	}
+	spyCat.Name = "Pichu"
+	spyCat.YearsOfExperience = 3
+	spyCat.Breed = "breed1"
+	err = service.Update(t.Context(), spyCat, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	updatedSpyCat, err := service.GetById(t.Context(), spyCat.Id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if updatedSpyCat.Name != "Pichu" || updatedSpyCat.YearsOfExperience != 3 || updatedSpyCat.Breed != "breed1" {
+		t.Fatal("spy cat was not updated")
+	}
+	if _, err = service.GetByName(t.Context(), "Pickachu"); !errors.Is(err, storage.ErrorModelNotFound) {
+		t.Fatal("Expected old name to be released")
+	}
+
+	spyCat.Name = "Raichu"
+	err = service.Update(t.Context(), spyCat, nil)
+	if !errors.Is(err, storage.ErrorUniqueConstraintViolation) {
+		t.Fatal("Expected unique name constraint violation")
+	}
 }
 
 func TestSpyCatsGetAll(t *testing.T) {
 	repo := memory.NewSpyCatRepository()
 	breedRepo := memory.NewBreedsRepository("breed1", "pokemon")
-	service := NewSpyCatService(repo, breedRepo)
+	service := NewSpyCatService(repo, breedRepo, discardLogger)
 	spyCat1 := &model.SpyCat{
 		Name:              "Pickachu",
 		YearsOfExperience: 2,
 		Breed:             "pokemon",
-		Salary:            100,
+		Salary:            usd(100),
 	}
 	err := service.Create(t.Context(), spyCat1)
 	if err != nil {
@@ -132,20 +446,25 @@ func TestSpyCatsGetAll(t *testing.T) {
 		Name:              "Charizard",
 		YearsOfExperience: 3,
 		Breed:             "pokemon",
-		Salary:            200,
+		Salary:            usd(200),
 	}
 	err = service.Create(t.Context(), spyCat2)
 	if err != nil {
 		t.Fatal(err)
 	}
 
-	spyCats, err := service.GetAll(t.Context())
+	spyCats, metadata, err := service.GetAll(t.Context(), model.SpyCatsFilter{
+		Filters: model.Filters{Page: 1, PageSize: 20, Sort: "id"},
+	})
 	if err != nil {
 		t.Fatal(err)
 	}
 	if len(spyCats) != 2 {
 		t.Fatal("Spy cats were not found")
 	}
+	if metadata.TotalRecords != 2 {
+		t.Fatal("wrong total records")
+	}
 	ids := map[int64]struct{}{
 		spyCat1.Id: {},
 		spyCat2.Id: {},
@@ -157,3 +476,208 @@ func TestSpyCatsGetAll(t *testing.T) {
 		t.Fatal("Spy cats were not found")
 	}
 }
+
+func TestSpyCatsGetAllFiltered(t *testing.T) {
+	minExperience := 2
+	maxSalary := usd(250)
+
+	tc := []struct {
+		name         string
+		filter       model.SpyCatsFilter
+		expected     []string
+		totalRecords int
+		lastPage     int
+	}{
+		{
+			name: "sorted by name",
+			filter: model.SpyCatsFilter{
+				Filters: model.Filters{Page: 1, PageSize: 20, Sort: "name"},
+			},
+			expected:     []string{"Bulbasaur", "Charizard", "Meowth", "Pickachu"},
+			totalRecords: 4,
+			lastPage:     1,
+		},
+		{
+			name: "sorted by salary descending",
+			filter: model.SpyCatsFilter{
+				Filters: model.Filters{Page: 1, PageSize: 20, Sort: "-salary"},
+			},
+			expected:     []string{"Meowth", "Charizard", "Bulbasaur", "Pickachu"},
+			totalRecords: 4,
+			lastPage:     1,
+		},
+		{
+			name: "second page",
+			filter: model.SpyCatsFilter{
+				Filters: model.Filters{Page: 2, PageSize: 3, Sort: "id"},
+			},
+			expected:     []string{"Meowth"},
+			totalRecords: 4,
+			lastPage:     2,
+		},
+		{
+			name: "page past the end",
+			filter: model.SpyCatsFilter{
+				Filters: model.Filters{Page: 5, PageSize: 3, Sort: "id"},
+			},
+			expected:     []string{},
+			totalRecords: 4,
+			lastPage:     2,
+		},
+		{
+			name: "filtered by breed and experience",
+			filter: model.SpyCatsFilter{
+				Breed:         "pokemon",
+				MinExperience: &minExperience,
+				Filters:       model.Filters{Page: 1, PageSize: 20, Sort: "id"},
+			},
+			expected:     []string{"Charizard", "Bulbasaur"},
+			totalRecords: 2,
+			lastPage:     1,
+		},
+		{
+			name: "filtered by breed in another case",
+			filter: model.SpyCatsFilter{
+				Breed:   " POKEMON ",
+				Filters: model.Filters{Page: 1, PageSize: 20, Sort: "id"},
+			},
+			expected:     []string{"Pickachu", "Charizard", "Bulbasaur"},
+			totalRecords: 3,
+			lastPage:     1,
+		},
+		{
+			name: "filtered by salary",
+			filter: model.SpyCatsFilter{
+				MaxSalary: &maxSalary,
+				Filters:   model.Filters{Page: 1, PageSize: 20, Sort: "-id"},
+			},
+			expected:     []string{"Bulbasaur", "Charizard", "Pickachu"},
+			totalRecords: 3,
+			lastPage:     1,
+		},
+	}
+
+	for _, tt := range tc {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := memory.NewSpyCatRepository()
+			breedRepo := memory.NewBreedsRepository("breed1", "pokemon")
+			service := NewSpyCatService(repo, breedRepo, discardLogger)
+			for _, spyCat := range []*model.SpyCat{
+				{Name: "Pickachu", YearsOfExperience: 1, Breed: "pokemon", Salary: usd(100)},
+				{Name: "Charizard", YearsOfExperience: 3, Breed: "pokemon", Salary: usd(200)},
+				{Name: "Bulbasaur", YearsOfExperience: 2, Breed: "pokemon", Salary: usd(150)},
+				{Name: "Meowth", YearsOfExperience: 5, Breed: "breed1", Salary: usd(300)},
+			} {
+				err := service.Create(t.Context(), spyCat)
+				if err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			spyCats, metadata, err := service.GetAll(t.Context(), tt.filter)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(spyCats) != len(tt.expected) {
+				t.Fatalf("expected %d spy cats, got %d", len(tt.expected), len(spyCats))
+			}
+			for i, spyCat := range spyCats {
+				if spyCat.Name != tt.expected[i] {
+					t.Fatalf("expected %s at position %d, got %s", tt.expected[i], i, spyCat.Name)
+				}
+			}
+			if metadata.TotalRecords != tt.totalRecords {
+				t.Fatalf("expected %d total records, got %d", tt.totalRecords, metadata.TotalRecords)
+			}
+			if metadata.LastPage != tt.lastPage {
+				t.Fatalf("expected last page %d, got %d", tt.lastPage, metadata.LastPage)
+			}
+		})
+	}
+}
+
+func TestSpyCatsGetAllFilteredWithoutBreeds(t *testing.T) {
+	repo := memory.NewSpyCatRepository()
+	service := NewSpyCatService(repo, unavailableBreeds{}, discardLogger)
+	for _, spyCat := range []*model.SpyCat{
+		{Name: "Tom", Breed: "Russian Blue", Salary: usd(100)},
+		{Name: "Felix", Breed: "Siamese", Salary: usd(100)},
+	} {
+		err := repo.Create(t.Context(), spyCat)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	spyCats, _, err := service.GetAll(t.Context(), model.SpyCatsFilter{
+		Breed:   " russian  BLUE",
+		Filters: model.Filters{Page: 1, PageSize: 20, Sort: "id"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(spyCats) != 1 || spyCats[0].Name != "Tom" {
+		t.Fatalf("Expected only Tom to be listed, got %v", spyCats)
+	}
+}
+
+func TestSpyCatsGetAllSortedBySalaryAcrossCurrencies(t *testing.T) {
+	repo := memory.NewSpyCatRepository()
+	breedRepo := memory.NewBreedsRepository("pokemon")
+	service := NewSpyCatService(repo, breedRepo, discardLogger)
+	for _, spyCat := range []*model.SpyCat{
+		{Name: "Pickachu", Breed: "pokemon", Salary: usd(100)},
+		{Name: "Charizard", Breed: "pokemon", Salary: model.Money{Amount: 20000, Currency: "JPY"}},
+		{Name: "Bulbasaur", Breed: "pokemon", Salary: usd(300)},
+		{Name: "Meowth", Breed: "pokemon", Salary: model.Money{Amount: 10000, Currency: "JPY"}},
+	} {
+		err := service.Create(t.Context(), spyCat)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tc := map[string][]string{
+		"salary":  {"Meowth", "Charizard", "Pickachu", "Bulbasaur"},
+		"-salary": {"Charizard", "Meowth", "Bulbasaur", "Pickachu"},
+	}
+	for sort, expected := range tc {
+		spyCats, _, err := service.GetAll(t.Context(), model.SpyCatsFilter{
+			Filters: model.Filters{Page: 1, PageSize: 20, Sort: sort},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		for i, spyCat := range spyCats {
+			if spyCat.Name != expected[i] {
+				t.Fatalf("sorted by %s: expected %s at position %d, got %s", sort, expected[i], i, spyCat.Name)
+			}
+		}
+	}
+}
+
+func TestSpyCatsValidateBreed(t *testing.T) {
+	repo := memory.NewSpyCatRepository()
+	breedRepo := memory.NewBreedsRepository("Bengal", "Russian Blue", "Siamese")
+	service := NewSpyCatService(repo, breedRepo, discardLogger)
+
+	breeds, err := service.GetBreeds(t.Context())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, input := range []string{"Russian Blue", " russian   BLUE ", "russian blue"} {
+		breed, ok := model.FindBreed(breeds, input)
+		if !ok || breed.Name != "Russian Blue" {
+			t.Fatalf("Expected %q to match Russian Blue", input)
+		}
+	}
+
+	spyCat := &model.SpyCat{Name: "Pickachu", Breed: "Bengel"}
+	spyCat.Password.Set("pa55word")
+	v := validator.New()
+	model.ValidateBreed(v, spyCat.Breed, breeds)
+	if v.Errors["breed"] != "invalid breed, did you mean: Bengal, Siamese, Russian Blue" {
+		t.Fatalf("unexpected breed error %q", v.Errors["breed"])
+	}
+}
@@ -2,14 +2,22 @@ package service
 
 import (
 	"context"
+	"errors"
 	"time"
 
 	"github.com/m1crogravity/spy-cat-agency/internal/model"
 )
 
+var ErrInvalidBatchSize = errors.New("batch size must be greater than zero")
+
 type TokenRepository interface {
 	Create(context.Context, *model.Token) error
 	FindByPlaintext(context.Context, string, string) (*model.Token, error)
+	RedeemInvitation(context.Context, string, *model.Agent) error
+	RedeemPasswordReset(context.Context, string, model.Password) error
+	DeleteByHash(context.Context, []byte) error
+	DeleteAllForUser(context.Context, int64, model.UserType) error
+	DeleteExpired(context.Context, time.Time, int) (int64, error)
 }
 
 type TokenService struct {
@@ -36,3 +44,50 @@ func (s *TokenService) Create(ctx context.Context, userID int64, userType model.
 func (s *TokenService) GetTokenByPlaintext(ctx context.Context, tokenPlaintext, tokenScope string) (*model.Token, error) {
 	return s.repository.FindByPlaintext(ctx, tokenPlaintext, tokenScope)
 }
+
+// RedeemInvitation uses up the invitation token to create the agent. The
+// token stays valid when the agent can't be created, and can't be redeemed
+// twice by concurrent requests.
+func (s *TokenService) RedeemInvitation(ctx context.Context, tokenPlaintext string, agent *model.Agent) error {
+	return s.repository.RedeemInvitation(ctx, tokenPlaintext, agent)
+}
+
+// RedeemPasswordReset uses up the password reset token to set a new password
+// for its user and revokes all the other tokens of the user.
+func (s *TokenService) RedeemPasswordReset(ctx context.Context, tokenPlaintext, password string) error {
+	var hashed model.Password
+	err := hashed.Set(password)
+	if err != nil {
+		return err
+	}
+
+	return s.repository.RedeemPasswordReset(ctx, tokenPlaintext, hashed)
+}
+
+func (s *TokenService) Revoke(ctx context.Context, token *model.Token) error {
+	return s.repository.DeleteByHash(ctx, token.Hash)
+}
+
+func (s *TokenService) RevokeAllForUser(ctx context.Context, userID int64, userType model.UserType) error {
+	return s.repository.DeleteAllForUser(ctx, userID, userType)
+}
+
+func (s *TokenService) PurgeExpired(ctx context.Context, batchSize int) (int64, error) {
+	if batchSize < 1 {
+		return 0, ErrInvalidBatchSize
+	}
+
+	now := time.Now()
+
+	var total int64
+	for {
+		deleted, err := s.repository.DeleteExpired(ctx, now, batchSize)
+		total += deleted
+		if err != nil {
+			return total, err
+		}
+		if deleted < int64(batchSize) {
+			return total, nil
+		}
+	}
+}
@@ -0,0 +1,218 @@
+package service
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/m1crogravity/spy-cat-agency/internal/model"
+	"github.com/m1crogravity/spy-cat-agency/internal/storage"
+	"github.com/m1crogravity/spy-cat-agency/internal/storage/memory"
+)
+
+func TestTokensRevoke(t *testing.T) {
+	repo := memory.NewTokensRepository()
+	service := NewTokensService(repo)
+
+	token, err := service.Create(t.Context(), 1, model.SpyCatUserType, time.Hour, model.ScopeAuthentication)
+	if err != nil {
+		t.Fatal(err)
+	}
+	other, err := service.Create(t.Context(), 1, model.SpyCatUserType, time.Hour, model.ScopeAuthentication)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = service.Revoke(t.Context(), token)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = service.GetTokenByPlaintext(t.Context(), token.Plaintext, model.ScopeAuthentication)
+	if !errors.Is(err, storage.ErrorModelNotFound) {
+		t.Fatal("revoked token is still valid")
+	}
+	_, err = service.GetTokenByPlaintext(t.Context(), other.Plaintext, model.ScopeAuthentication)
+	if err != nil {
+		t.Fatal("other token was revoked")
+	}
+}
+
+func TestTokensRevokeAllForUser(t *testing.T) {
+	repo := memory.NewTokensRepository()
+	service := NewTokensService(repo)
+
+	tokens := make([]*model.Token, 0, 2)
+	for range 2 {
+		token, err := service.Create(t.Context(), 1, model.SpyCatUserType, time.Hour, model.ScopeAuthentication)
+		if err != nil {
+			t.Fatal(err)
+		}
+		tokens = append(tokens, token)
+	}
+	agentToken, err := service.Create(t.Context(), 1, model.AgentUserType, time.Hour, model.ScopeAuthentication)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = service.RevokeAllForUser(t.Context(), 1, model.SpyCatUserType)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, token := range tokens {
+		_, err = service.GetTokenByPlaintext(t.Context(), token.Plaintext, model.ScopeAuthentication)
+		if !errors.Is(err, storage.ErrorModelNotFound) {
+			t.Fatal("spy cat token is still valid")
+		}
+	}
+	_, err = service.GetTokenByPlaintext(t.Context(), agentToken.Plaintext, model.ScopeAuthentication)
+	if err != nil {
+		t.Fatal("agent with the same id lost its token")
+	}
+}
+
+func TestTokensRedeemInvitation(t *testing.T) {
+	agentsRepo := memory.NewAgentsRepository()
+	repo := memory.NewTokensRepository().WithUsers(agentsRepo, memory.NewSpyCatRepository())
+	service := NewTokensService(repo)
+
+	err := agentsRepo.Create(t.Context(), &model.Agent{Name: "Taken"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	invitation, err := service.Create(t.Context(), 1, model.InvitationUserType, time.Hour, model.ScopeInvitation)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = service.RedeemInvitation(t.Context(), invitation.Plaintext, &model.Agent{Name: "Taken"})
+	if !errors.Is(err, storage.ErrorUniqueConstraintViolation) {
+		t.Fatalf("Expected error to be storage.ErrorUniqueConstraintViolation, got %v", err)
+	}
+
+	agent := &model.Agent{Name: "James"}
+	err = service.RedeemInvitation(t.Context(), invitation.Plaintext, agent)
+	if err != nil {
+		t.Fatal("invitation was used up by the failed redemption")
+	}
+	if agent.Id == 0 {
+		t.Fatal("agent was not created")
+	}
+
+	err = service.RedeemInvitation(t.Context(), invitation.Plaintext, &model.Agent{Name: "Bond"})
+	if !errors.Is(err, storage.ErrorModelNotFound) {
+		t.Fatal("invitation was redeemed twice")
+	}
+}
+
+func TestTokensRedeemPasswordReset(t *testing.T) {
+	spyCatsRepo := memory.NewSpyCatRepository()
+	repo := memory.NewTokensRepository().WithUsers(memory.NewAgentsRepository(), spyCatsRepo)
+	service := NewTokensService(repo)
+
+	spyCat := &model.SpyCat{Name: "Pickachu"}
+	err := spyCatsRepo.Create(t.Context(), spyCat)
+	if err != nil {
+		t.Fatal(err)
+	}
+	authentication, err := service.Create(t.Context(), spyCat.Id, model.SpyCatUserType, time.Hour, model.ScopeAuthentication)
+	if err != nil {
+		t.Fatal(err)
+	}
+	reset, err := service.Create(t.Context(), spyCat.Id, model.SpyCatUserType, time.Hour, model.ScopePasswordReset)
+	if err != nil {
+		t.Fatal(err)
+	}
+	unknown, err := service.Create(t.Context(), spyCat.Id+1, model.SpyCatUserType, time.Hour, model.ScopePasswordReset)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = service.RedeemPasswordReset(t.Context(), reset.Plaintext, "new-password")
+	if err != nil {
+		t.Fatal(err)
+	}
+	updatedSpyCat, err := spyCatsRepo.FindById(t.Context(), spyCat.Id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	match, err := updatedSpyCat.Password.Matches("new-password")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !match {
+		t.Fatal("password was not reset")
+	}
+	_, err = service.GetTokenByPlaintext(t.Context(), authentication.Plaintext, model.ScopeAuthentication)
+	if !errors.Is(err, storage.ErrorModelNotFound) {
+		t.Fatal("spy cat token is still valid")
+	}
+
+	err = service.RedeemPasswordReset(t.Context(), reset.Plaintext, "new-password")
+	if !errors.Is(err, storage.ErrorModelNotFound) {
+		t.Fatal("password reset token was redeemed twice")
+	}
+
+	err = service.RedeemPasswordReset(t.Context(), unknown.Plaintext, "new-password")
+	if !errors.Is(err, storage.ErrorModelNotFound) {
+		t.Fatal("Expected error to be storage.ErrorModelNotFound")
+	}
+	_, err = service.GetTokenByPlaintext(t.Context(), unknown.Plaintext, model.ScopePasswordReset)
+	if err != nil {
+		t.Fatal("token of an unknown user was used up")
+	}
+}
+
+func TestTokensRevokeAllForUserKeepsInvitations(t *testing.T) {
+	repo := memory.NewTokensRepository()
+	service := NewTokensService(repo)
+
+	invitation, err := service.Create(t.Context(), 1, model.InvitationUserType, time.Hour, model.ScopeInvitation)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = service.RevokeAllForUser(t.Context(), 1, model.AgentUserType)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = service.GetTokenByPlaintext(t.Context(), invitation.Plaintext, model.ScopeInvitation)
+	if err != nil {
+		t.Fatal("invitation was revoked together with the inviting agent tokens")
+	}
+}
+
+func TestTokensPurgeExpired(t *testing.T) {
+	repo := memory.NewTokensRepository()
+	service := NewTokensService(repo)
+
+	for range 5 {
+		_, err := service.Create(t.Context(), 1, model.AgentUserType, -time.Minute, model.ScopeAuthentication)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	active, err := service.Create(t.Context(), 1, model.AgentUserType, time.Hour, model.ScopeAuthentication)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	deleted, err := service.PurgeExpired(t.Context(), 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if deleted != 5 {
+		t.Fatalf("expected 5 purged tokens, got %d", deleted)
+	}
+	_, err = service.GetTokenByPlaintext(t.Context(), active.Plaintext, model.ScopeAuthentication)
+	if err != nil {
+		t.Fatal("active token was purged")
+	}
+
+	_, err = service.PurgeExpired(t.Context(), 0)
+	if !errors.Is(err, ErrInvalidBatchSize) {
+		t.Fatal("expected invalid batch size error")
+	}
+}
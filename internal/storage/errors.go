@@ -5,4 +5,5 @@ import "errors"
 var (
 	ErrorModelNotFound             = errors.New("model not found")
 	ErrorUniqueConstraintViolation = errors.New("model should be unique")
+	ErrorEditConflict              = errors.New("model was changed concurrently")
 )
@@ -51,3 +51,28 @@ func (r *AgentsRepository) FindById(ctx context.Context, id int64) (*model.Agent
 	}
 	return agent, nil
 }
+
+func (r *AgentsRepository) UpdateRole(ctx context.Context, agent *model.Agent) error {
+	stored, ok := r.agents[agent.Id]
+	if !ok {
+		return storage.ErrorModelNotFound
+	}
+	stored.Role = agent.Role
+	return nil
+}
+
+func (r *AgentsRepository) CreateIfEmpty(ctx context.Context, agent *model.Agent) (bool, error) {
+	if len(r.agents) > 0 {
+		return false, nil
+	}
+	return true, r.Create(ctx, agent)
+}
+
+func (r *AgentsRepository) UpdatePassword(ctx context.Context, agent *model.Agent) error {
+	stored, ok := r.agents[agent.Id]
+	if !ok {
+		return storage.ErrorModelNotFound
+	}
+	stored.Password = agent.Password
+	return nil
+}
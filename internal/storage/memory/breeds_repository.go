@@ -1,17 +1,29 @@
 package memory
 
-import "context"
+import (
+	"context"
+	"strings"
+
+	"github.com/m1crogravity/spy-cat-agency/internal/model"
+)
 
 type BreedsRepository struct {
-	breeds []string
+	breeds []model.Breed
 }
 
-func NewBreedsRepository(breeds ...string) *BreedsRepository {
+// NewBreedsRepository creates a repository of breeds with the given names.
+// The id of a breed is its lower-cased name.
+func NewBreedsRepository(names ...string) *BreedsRepository {
+	breeds := make([]model.Breed, len(names))
+	for i, name := range names {
+		breeds[i] = model.Breed{Id: strings.ToLower(name), Name: name}
+	}
+
 	return &BreedsRepository{
 		breeds: breeds,
 	}
 }
 
-func (r *BreedsRepository) FindAll(ctx context.Context) ([]string, error) {
+func (r *BreedsRepository) FindAll(ctx context.Context) ([]model.Breed, error) {
 	return r.breeds, nil
 }
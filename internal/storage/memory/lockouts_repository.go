@@ -0,0 +1,89 @@
+package memory
+
+import (
+	"context"
+	"slices"
+	"time"
+
+	"github.com/m1crogravity/spy-cat-agency/internal/model"
+	"github.com/m1crogravity/spy-cat-agency/internal/storage"
+)
+
+type lockoutKey struct {
+	userType model.UserType
+	name     string
+}
+
+type LockoutsRepository struct {
+	lockouts map[lockoutKey]*model.Lockout
+}
+
+func NewLockoutsRepository() *LockoutsRepository {
+	return &LockoutsRepository{
+		lockouts: make(map[lockoutKey]*model.Lockout),
+	}
+}
+
+func (r *LockoutsRepository) Find(ctx context.Context, userType model.UserType, name string) (*model.Lockout, error) {
+	lockout, ok := r.lockouts[lockoutKey{userType, name}]
+	if !ok {
+		return nil, storage.ErrorModelNotFound
+	}
+
+	l := *lockout
+	return &l, nil
+}
+
+func (r *LockoutsRepository) FindAllLocked(ctx context.Context, now time.Time) ([]*model.Lockout, error) {
+	var lockouts []*model.Lockout
+	for _, lockout := range r.lockouts {
+		if lockout.IsLocked(now) {
+			l := *lockout
+			lockouts = append(lockouts, &l)
+		}
+	}
+	slices.SortFunc(lockouts, func(a, b *model.Lockout) int {
+		return b.LockedUntil.Compare(a.LockedUntil)
+	})
+
+	return lockouts, nil
+}
+
+func (r *LockoutsRepository) RegisterAttempt(ctx context.Context, userType model.UserType, name string, now, resetBefore time.Time, lockFor func(int) time.Duration) (*model.Lockout, bool, error) {
+	key := lockoutKey{userType, name}
+	lockout, ok := r.lockouts[key]
+	if !ok {
+		lockout = &model.Lockout{UserType: userType, Name: name, LastFailureAt: now}
+		r.lockouts[key] = lockout
+	}
+
+	counted := lockout.Attempt(now, resetBefore, lockFor)
+
+	l := *lockout
+	return &l, counted, nil
+}
+
+func (r *LockoutsRepository) Delete(ctx context.Context, userType model.UserType, name string) error {
+	key := lockoutKey{userType, name}
+	if _, ok := r.lockouts[key]; !ok {
+		return storage.ErrorModelNotFound
+	}
+
+	delete(r.lockouts, key)
+	return nil
+}
+
+func (r *LockoutsRepository) DeleteStale(ctx context.Context, now, resetBefore time.Time, limit int) (int64, error) {
+	var deleted int64
+	for key, lockout := range r.lockouts {
+		if deleted >= int64(limit) {
+			break
+		}
+		if lockout.LastFailureAt.Before(resetBefore) && !lockout.IsLocked(now) {
+			delete(r.lockouts, key)
+			deleted++
+		}
+	}
+
+	return deleted, nil
+}
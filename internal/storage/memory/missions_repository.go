@@ -1,9 +1,10 @@
 package memory
 
 import (
+	"cmp"
 	"context"
-	"maps"
 	"slices"
+	"time"
 
 	"github.com/m1crogravity/spy-cat-agency/internal/model"
 	"github.com/m1crogravity/spy-cat-agency/internal/storage"
@@ -11,15 +12,28 @@ import (
 
 type MissionsRepository struct {
 	missions      map[int64]*model.Mission
+	states        map[int64]model.CompleteState
 	lastMissionId int64
+	handoffs      []*model.MissionHandoff
+	lastHandoffId int64
+	spyCats       *SpyCatsRepository
 }
 
 func NewMissionsRepository() *MissionsRepository {
 	return &MissionsRepository{
 		missions: make(map[int64]*model.Mission),
+		states:   make(map[int64]model.CompleteState),
 	}
 }
 
+// WithSpyCats makes SaveMission refuse to leave a mission in progress with an
+// archived spy cat of the given repository, the way the spy cat row lock does
+// in postgres.
+func (r *MissionsRepository) WithSpyCats(spyCats *SpyCatsRepository) *MissionsRepository {
+	r.spyCats = spyCats
+	return r
+}
+
 func (r *MissionsRepository) CreateMission(ctx context.Context, mission *model.Mission) error {
 	missionId := r.lastMissionId + 1
 	r.lastMissionId = missionId
@@ -32,6 +46,7 @@ func (r *MissionsRepository) CreateMission(ctx context.Context, mission *model.M
 		}
 	}
 	r.missions[missionId] = mission
+	r.states[missionId] = mission.State
 	return nil
 }
 
@@ -49,13 +64,26 @@ func (r *MissionsRepository) DeleteMission(ctx context.Context, id int64) error
 	}
 
 	delete(r.missions, id)
+	delete(r.states, id)
 	return nil
 }
 
-func (r *MissionsRepository) SaveMission(ctx context.Context, mission *model.Mission) error {
+// SaveMission saves the mission moved out of the from state. Missions are
+// shared with the callers, so the last saved state is kept apart to detect
+// edit conflicts.
+func (r *MissionsRepository) SaveMission(ctx context.Context, mission *model.Mission, from model.CompleteState) error {
 	if _, ok := r.missions[mission.Id]; !ok {
 		return storage.ErrorModelNotFound
 	}
+	if r.spyCats != nil && mission.State == model.InProgress && mission.IsAssignedToCat() {
+		spyCat, ok := r.spyCats.spyCats[mission.AssignedCatId]
+		if !ok || spyCat.IsArchived() {
+			return storage.ErrorModelNotFound
+		}
+	}
+	if r.states[mission.Id] != from {
+		return storage.ErrorEditConflict
+	}
 	for _, target := range mission.Targets {
 		if target.Id != 0 {
 			target.MissionId = mission.Id
@@ -65,9 +93,35 @@ func (r *MissionsRepository) SaveMission(ctx context.Context, mission *model.Mis
 		r.CreateTarget(ctx, mission, target)
 	}
 	r.missions[mission.Id] = mission
+	r.states[mission.Id] = mission.State
+	return nil
+}
+
+func (r *MissionsRepository) SaveMissionHandoff(ctx context.Context, mission *model.Mission, from model.CompleteState, handoff *model.MissionHandoff) error {
+	err := r.SaveMission(ctx, mission, from)
+	if err != nil {
+		return err
+	}
+
+	r.lastHandoffId++
+	handoff.Id = r.lastHandoffId
+	handoff.CreatedAt = time.Now()
+	saved := *handoff
+	r.handoffs = append(r.handoffs, &saved)
 	return nil
 }
 
+func (r *MissionsRepository) FindMissionHandoffs(ctx context.Context, missionId int64) ([]*model.MissionHandoff, error) {
+	handoffs := make([]*model.MissionHandoff, 0)
+	for _, handoff := range r.handoffs {
+		if handoff.MissionId == missionId {
+			handoffs = append(handoffs, handoff)
+		}
+	}
+
+	return handoffs, nil
+}
+
 func (r *MissionsRepository) SaveTarget(ctx context.Context, target *model.Target) error {
 	return nil
 }
@@ -92,7 +146,7 @@ func (r *MissionsRepository) DeleteTarget(ctx context.Context, mission *model.Mi
 
 func (r *MissionsRepository) FindActiveMission(ctx context.Context, spyCatId int64) (*model.Mission, error) {
 	for _, mission := range r.missions {
-		if mission.AssignedCatId == spyCatId && !mission.IsCompleted() {
+		if mission.AssignedCatId == spyCatId && !mission.IsFinished() {
 			return mission, nil
 		}
 	}
@@ -100,6 +154,30 @@ func (r *MissionsRepository) FindActiveMission(ctx context.Context, spyCatId int
 	return nil, storage.ErrorModelNotFound
 }
 
-func (r *MissionsRepository) FindAll(ctx context.Context) ([]*model.Mission, error) {
-	return slices.Collect(maps.Values(r.missions)), nil
+func (r *MissionsRepository) FindAll(ctx context.Context, filter model.MissionsFilter) ([]*model.Mission, error) {
+	missions := make([]*model.Mission, 0, len(r.missions))
+	for _, mission := range r.missions {
+		if filter.Matches(mission) {
+			missions = append(missions, mission)
+		}
+	}
+
+	slices.SortFunc(missions, func(a, b *model.Mission) int {
+		return cmp.Compare(a.Id, b.Id)
+	})
+
+	if filter.Limit > 0 && len(missions) > filter.Limit {
+		missions = missions[:filter.Limit]
+	}
+
+	return missions, nil
+}
+
+func (r *MissionsRepository) CountByState(ctx context.Context) (map[model.CompleteState]int, error) {
+	counts := make(map[model.CompleteState]int)
+	for _, mission := range r.missions {
+		counts[mission.State]++
+	}
+
+	return counts, nil
 }
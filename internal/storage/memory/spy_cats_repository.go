@@ -1,16 +1,22 @@
 package memory
 
 import (
+	"cmp"
 	"context"
+	"slices"
+	"time"
 
 	"github.com/m1crogravity/spy-cat-agency/internal/model"
 	"github.com/m1crogravity/spy-cat-agency/internal/storage"
 )
 
 type SpyCatsRepository struct {
-	spyCats map[int64]*model.SpyCat
-	names   map[string]int64
-	lastId  int64
+	spyCats            map[int64]*model.SpyCat
+	names              map[string]int64
+	lastId             int64
+	salaryChanges      []*model.SalaryChange
+	lastSalaryChangeId int64
+	missions           *MissionsRepository
 }
 
 func NewSpyCatRepository() *SpyCatsRepository {
@@ -20,12 +26,22 @@ func NewSpyCatRepository() *SpyCatsRepository {
 	}
 }
 
+// WithMissions makes Delete refuse spy cats with an active mission in the
+// given repository, the way the missions table does in postgres.
+func (r *SpyCatsRepository) WithMissions(missions *MissionsRepository) *SpyCatsRepository {
+	r.missions = missions
+	return r
+}
+
 func (r *SpyCatsRepository) Create(ctx context.Context, spyCat *model.SpyCat) error {
 	if _, ok := r.names[spyCat.Name]; ok {
 		return storage.ErrorUniqueConstraintViolation
 	}
 	id := r.lastId + 1
 	spyCat.Id = id
+	if spyCat.CreatedAt.IsZero() {
+		spyCat.CreatedAt = time.Now()
+	}
 	r.spyCats[id] = spyCat
 	r.names[spyCat.Name] = id
 	r.lastId = id
@@ -38,36 +54,163 @@ func (r *SpyCatsRepository) FindById(ctx context.Context, id int64) (*model.SpyC
 		return nil, storage.ErrorModelNotFound
 	}
 
-	return spyCat, nil
+	found := *spyCat
+	return &found, nil
 }
 
 func (r *SpyCatsRepository) Delete(ctx context.Context, id int64) error {
-	if _, ok := r.spyCats[id]; !ok {
+	spyCat, ok := r.spyCats[id]
+	if !ok || spyCat.IsArchived() {
 		return storage.ErrorModelNotFound
 	}
+	if r.missions != nil {
+		if _, err := r.missions.FindActiveMission(ctx, id); err == nil {
+			return storage.ErrorEditConflict
+		}
+	}
 
-	delete(r.spyCats, id)
+	deletedAt := time.Now()
+	spyCat.DeletedAt = &deletedAt
 	return nil
 }
 
-func (r *SpyCatsRepository) Save(ctx context.Context, spyCat model.SpyCat) error {
+func (r *SpyCatsRepository) Restore(ctx context.Context, id int64) error {
+	spyCat, ok := r.spyCats[id]
+	if !ok || !spyCat.IsArchived() {
+		return storage.ErrorModelNotFound
+	}
+
+	spyCat.DeletedAt = nil
+	return nil
+}
+
+func (r *SpyCatsRepository) Save(ctx context.Context, spyCat model.SpyCat, change *model.SalaryChange) error {
 	spyCatToUpdate, ok := r.spyCats[spyCat.Id]
 	if !ok {
 		return storage.ErrorModelNotFound
 	}
 
+	if id, ok := r.names[spyCat.Name]; ok && id != spyCat.Id {
+		return storage.ErrorUniqueConstraintViolation
+	}
+
+	oldSalary := spyCat.Salary
+	if change != nil {
+		oldSalary = change.OldSalary
+	}
+	if spyCatToUpdate.Salary != oldSalary {
+		return storage.ErrorEditConflict
+	}
+	if change != nil {
+		for _, saved := range r.salaryChanges {
+			if saved.SpyCatId == change.SpyCatId && saved.EffectiveDate.After(change.EffectiveDate) {
+				return model.ErrBackdatedSalaryChange
+			}
+		}
+	}
+
+	delete(r.names, spyCatToUpdate.Name)
+	r.names[spyCat.Name] = spyCat.Id
+	spyCatToUpdate.Name = spyCat.Name
+	spyCatToUpdate.YearsOfExperience = spyCat.YearsOfExperience
+	spyCatToUpdate.Breed = spyCat.Breed
 	spyCatToUpdate.Salary = spyCat.Salary
 
+	if change != nil {
+		r.lastSalaryChangeId++
+		change.Id = r.lastSalaryChangeId
+		change.CreatedAt = time.Now()
+		saved := *change
+		r.salaryChanges = append(r.salaryChanges, &saved)
+	}
+
 	return nil
 }
 
-func (r *SpyCatsRepository) FindAll(ctx context.Context) ([]*model.SpyCat, error) {
+func (r *SpyCatsRepository) FindSalaryChanges(ctx context.Context, spyCatId int64) ([]*model.SalaryChange, error) {
+	changes := make([]*model.SalaryChange, 0)
+	for _, change := range r.salaryChanges {
+		if change.SpyCatId == spyCatId {
+			changes = append(changes, change)
+		}
+	}
+	sortSalaryChanges(changes)
+
+	return changes, nil
+}
+
+func (r *SpyCatsRepository) FindSalaryChangesBetween(ctx context.Context, after, until time.Time) ([]*model.SalaryChange, error) {
+	sorted := slices.Clone(r.salaryChanges)
+	sortSalaryChanges(sorted)
+
+	changes := make([]*model.SalaryChange, 0)
+	pastUntil := make(map[int64]bool)
+	for _, change := range sorted {
+		switch {
+		case !change.EffectiveDate.After(after):
+		case !change.EffectiveDate.After(until):
+			changes = append(changes, change)
+		case !pastUntil[change.SpyCatId]:
+			pastUntil[change.SpyCatId] = true
+			changes = append(changes, change)
+		}
+	}
+
+	return changes, nil
+}
+
+func sortSalaryChanges(changes []*model.SalaryChange) {
+	slices.SortStableFunc(changes, func(a, b *model.SalaryChange) int {
+		return cmp.Or(
+			cmp.Compare(a.SpyCatId, b.SpyCatId),
+			a.EffectiveDate.Compare(b.EffectiveDate),
+			cmp.Compare(a.Id, b.Id),
+		)
+	})
+}
+
+func (r *SpyCatsRepository) FindAll(ctx context.Context, filter model.SpyCatsFilter) ([]*model.SpyCat, int, error) {
 	spyCats := make([]*model.SpyCat, 0, len(r.spyCats))
 	for _, spyCat := range r.spyCats {
-		spyCats = append(spyCats, spyCat)
+		if filter.Matches(spyCat) {
+			spyCats = append(spyCats, spyCat)
+		}
 	}
 
-	return spyCats, nil
+	slices.SortFunc(spyCats, func(a, b *model.SpyCat) int {
+		var c int
+		switch filter.SortColumn() {
+		case "name":
+			c = cmp.Compare(a.Name, b.Name)
+		case "years_of_experience":
+			c = cmp.Compare(a.YearsOfExperience, b.YearsOfExperience)
+		case "salary":
+			// Amounts of different currencies can't be compared, so salaries
+			// are grouped by currency, in ascending order either way.
+			if byCurrency := cmp.Compare(a.Salary.Currency, b.Salary.Currency); byCurrency != 0 {
+				return byCurrency
+			}
+			c = cmp.Compare(a.Salary.Amount, b.Salary.Amount)
+		default:
+			c = cmp.Compare(a.Id, b.Id)
+		}
+		if filter.SortDescending() {
+			c = -c
+		}
+		if c == 0 {
+			c = cmp.Compare(a.Id, b.Id)
+		}
+		return c
+	})
+
+	totalRecords := len(spyCats)
+	if filter.PageSize > 0 {
+		start := min(filter.Offset(), totalRecords)
+		end := min(start+filter.Limit(), totalRecords)
+		spyCats = spyCats[start:end]
+	}
+
+	return spyCats, totalRecords, nil
 }
 
 func (r *SpyCatsRepository) FindByName(ctx context.Context, name string) (*model.SpyCat, error) {
@@ -81,5 +224,15 @@ func (r *SpyCatsRepository) FindByName(ctx context.Context, name string) (*model
 		panic("spy cat repository data inconsistency")
 	}
 
-	return spyCat, nil
+	found := *spyCat
+	return &found, nil
+}
+
+func (r *SpyCatsRepository) UpdatePassword(ctx context.Context, spyCat *model.SpyCat) error {
+	stored, ok := r.spyCats[spyCat.Id]
+	if !ok || stored.IsArchived() {
+		return storage.ErrorModelNotFound
+	}
+	stored.Password = spyCat.Password
+	return nil
 }
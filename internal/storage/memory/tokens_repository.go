@@ -1,7 +1,9 @@
 package memory
 
 import (
+	"bytes"
 	"context"
+	"fmt"
 	"time"
 
 	"github.com/m1crogravity/spy-cat-agency/internal/model"
@@ -9,7 +11,9 @@ import (
 )
 
 type TokensRepository struct {
-	tokens map[string]*model.Token
+	tokens  map[string]*model.Token
+	agents  *AgentsRepository
+	spyCats *SpyCatsRepository
 }
 
 func NewTokensRepository() *TokensRepository {
@@ -18,6 +22,14 @@ func NewTokensRepository() *TokensRepository {
 	}
 }
 
+// WithUsers gives the repository the agents and spy cats that redeemed tokens
+// act on, the way the agents and spy_cats tables do in postgres.
+func (r *TokensRepository) WithUsers(agents *AgentsRepository, spyCats *SpyCatsRepository) *TokensRepository {
+	r.agents = agents
+	r.spyCats = spyCats
+	return r
+}
+
 func (r *TokensRepository) Create(ctx context.Context, token *model.Token) error {
 	if _, ok := r.tokens[token.Plaintext]; ok {
 		return storage.ErrorUniqueConstraintViolation
@@ -35,3 +47,77 @@ func (r *TokensRepository) FindByPlaintext(ctx context.Context, tokenPLaintext s
 
 	return token, nil
 }
+
+// RedeemInvitation deletes the invitation token only once the agent is
+// created, like the rolled back transaction in postgres.
+func (r *TokensRepository) RedeemInvitation(ctx context.Context, tokenPlaintext string, agent *model.Agent) error {
+	_, err := r.FindByPlaintext(ctx, tokenPlaintext, model.ScopeInvitation)
+	if err != nil {
+		return err
+	}
+
+	err = r.agents.Create(ctx, agent)
+	if err != nil {
+		return err
+	}
+
+	delete(r.tokens, tokenPlaintext)
+	return nil
+}
+
+func (r *TokensRepository) RedeemPasswordReset(ctx context.Context, tokenPlaintext string, password model.Password) error {
+	token, err := r.FindByPlaintext(ctx, tokenPlaintext, model.ScopePasswordReset)
+	if err != nil {
+		return err
+	}
+
+	switch token.UserType {
+	case model.SpyCatUserType:
+		err = r.spyCats.UpdatePassword(ctx, &model.SpyCat{Id: token.UserID, Password: password})
+	case model.AgentUserType:
+		err = r.agents.UpdatePassword(ctx, &model.Agent{Id: token.UserID, Password: password})
+	default:
+		err = fmt.Errorf("unsupported token user type %q", token.UserType)
+	}
+	if err != nil {
+		return err
+	}
+
+	delete(r.tokens, tokenPlaintext)
+	return r.DeleteAllForUser(ctx, token.UserID, token.UserType)
+}
+
+func (r *TokensRepository) DeleteByHash(ctx context.Context, hash []byte) error {
+	for plaintext, token := range r.tokens {
+		if bytes.Equal(token.Hash, hash) {
+			delete(r.tokens, plaintext)
+		}
+	}
+
+	return nil
+}
+
+func (r *TokensRepository) DeleteAllForUser(ctx context.Context, userID int64, userType model.UserType) error {
+	for plaintext, token := range r.tokens {
+		if token.UserID == userID && token.UserType == userType {
+			delete(r.tokens, plaintext)
+		}
+	}
+
+	return nil
+}
+
+func (r *TokensRepository) DeleteExpired(ctx context.Context, before time.Time, limit int) (int64, error) {
+	var deleted int64
+	for plaintext, token := range r.tokens {
+		if deleted >= int64(limit) {
+			break
+		}
+		if token.Expiry.Before(before) {
+			delete(r.tokens, plaintext)
+			deleted++
+		}
+	}
+
+	return deleted, nil
+}
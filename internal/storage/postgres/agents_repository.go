@@ -11,12 +11,14 @@ import (
 )
 
 type AgentsRepository struct {
-	queries *sqlc.Queries
+	queries    *sqlc.Queries
+	connection Connection
 }
 
-func NewAgentsRepository(conn sqlc.DBTX) *AgentsRepository {
+func NewAgentsRepository(conn Connection) *AgentsRepository {
 	return &AgentsRepository{
-		queries: sqlc.New(conn),
+		queries:    sqlc.New(conn),
+		connection: conn,
 	}
 }
 
@@ -29,14 +31,52 @@ func (r *AgentsRepository) FindByName(ctx context.Context, name string) (*model.
 	return &model.Agent{
 		Id:       agent.ID,
 		Name:     agent.Name,
+		Role:     model.Role(agent.Role),
 		Password: *model.NewPasswordFromHash(agent.PasswordHash),
 	}, nil
 }
 
 func (r *AgentsRepository) Create(ctx context.Context, agent *model.Agent) error {
-	id, err := r.queries.CreateAgent(ctx, sqlc.CreateAgentParams{
+	return createAgent(ctx, r.queries, agent)
+}
+
+// CreateIfEmpty creates the agent only when there are no agents yet. The
+// table is locked until the agent is created, so concurrent calls can't both
+// see it empty.
+func (r *AgentsRepository) CreateIfEmpty(ctx context.Context, agent *model.Agent) (bool, error) {
+	tx, err := r.connection.Begin(ctx)
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback(ctx)
+
+	txQuery := r.queries.WithTx(tx)
+	err = txQuery.LockAgents(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	count, err := txQuery.CountAgents(ctx)
+	if err != nil {
+		return false, err
+	}
+	if count > 0 {
+		return false, nil
+	}
+
+	err = createAgent(ctx, txQuery, agent)
+	if err != nil {
+		return false, err
+	}
+
+	return true, tx.Commit(ctx)
+}
+
+func createAgent(ctx context.Context, queries *sqlc.Queries, agent *model.Agent) error {
+	id, err := queries.CreateAgent(ctx, sqlc.CreateAgentParams{
 		Name:         agent.Name,
 		PasswordHash: agent.Password.Hash,
+		Role:         string(agent.Role),
 	})
 	if err != nil {
 		var pgErr *pgconn.PgError
@@ -54,12 +94,34 @@ func (r *AgentsRepository) Create(ctx context.Context, agent *model.Agent) error
 func (r *AgentsRepository) FindById(ctx context.Context, id int64) (*model.Agent, error) {
 	agent, err := r.queries.FindAgentById(ctx, id)
 	if err != nil {
-		return nil, err
+		return nil, storage.ErrorModelNotFound
 	}
 
 	return &model.Agent{
 		Id:       agent.ID,
 		Name:     agent.Name,
+		Role:     model.Role(agent.Role),
 		Password: *model.NewPasswordFromHash(agent.PasswordHash),
 	}, nil
 }
+
+func (r *AgentsRepository) UpdateRole(ctx context.Context, agent *model.Agent) error {
+	return r.queries.UpdateAgentRole(ctx, sqlc.UpdateAgentRoleParams{
+		ID:   agent.Id,
+		Role: string(agent.Role),
+	})
+}
+
+func (r *AgentsRepository) UpdatePassword(ctx context.Context, agent *model.Agent) error {
+	rows, err := r.queries.UpdateAgentPassword(ctx, sqlc.UpdateAgentPasswordParams{
+		ID:           agent.Id,
+		PasswordHash: agent.Password.Hash,
+	})
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return storage.ErrorModelNotFound
+	}
+	return nil
+}
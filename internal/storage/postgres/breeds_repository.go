@@ -0,0 +1,74 @@
+package postgres
+
+import (
+	"context"
+
+	"github.com/m1crogravity/spy-cat-agency/internal/model"
+	"github.com/m1crogravity/spy-cat-agency/internal/storage/postgres/sqlc"
+)
+
+// BreedsRepository keeps the last breed list received from TheCatAPI, so it
+// can be served when the API is unavailable.
+type BreedsRepository struct {
+	queries    *sqlc.Queries
+	connection Connection
+}
+
+func NewBreedsRepository(conn Connection) *BreedsRepository {
+	return &BreedsRepository{
+		queries:    sqlc.New(conn),
+		connection: conn,
+	}
+}
+
+func (r *BreedsRepository) FindAll(ctx context.Context) ([]model.Breed, error) {
+	rows, err := r.queries.ListBreeds(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	breeds := make([]model.Breed, len(rows))
+	for i, row := range rows {
+		breeds[i] = model.Breed{
+			Id:          row.ID,
+			Name:        row.Name,
+			Origin:      row.Origin,
+			Temperament: row.Temperament,
+			LifeSpan:    row.LifeSpan,
+		}
+	}
+
+	return breeds, nil
+}
+
+func (r *BreedsRepository) ReplaceAll(ctx context.Context, breeds []model.Breed) error {
+	tx, err := r.connection.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	txQuery := r.queries.WithTx(tx)
+	err = txQuery.DeleteAllBreeds(ctx)
+	if err != nil {
+		return err
+	}
+
+	params := make([]sqlc.CreateBreedsParams, len(breeds))
+	for i, breed := range breeds {
+		params[i] = sqlc.CreateBreedsParams{
+			ID:          breed.Id,
+			Name:        breed.Name,
+			Origin:      breed.Origin,
+			Temperament: breed.Temperament,
+			LifeSpan:    breed.LifeSpan,
+		}
+	}
+
+	_, err = txQuery.CreateBreeds(ctx, params)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
@@ -0,0 +1,118 @@
+package postgres
+
+import (
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/m1crogravity/spy-cat-agency/internal/model"
+	"github.com/m1crogravity/spy-cat-agency/internal/storage"
+	"github.com/m1crogravity/spy-cat-agency/internal/storage/postgres/sqlc"
+)
+
+type LockoutsRepository struct {
+	queries    *sqlc.Queries
+	connection Connection
+}
+
+func NewLockoutsRepository(conn Connection) *LockoutsRepository {
+	return &LockoutsRepository{
+		queries:    sqlc.New(conn),
+		connection: conn,
+	}
+}
+
+func (r *LockoutsRepository) FindAllLocked(ctx context.Context, now time.Time) ([]*model.Lockout, error) {
+	rows, err := r.queries.ListLockedLockouts(ctx, pgtype.Timestamptz{Time: now, Valid: true})
+	if err != nil {
+		return nil, err
+	}
+
+	lockouts := make([]*model.Lockout, 0, len(rows))
+	for _, row := range rows {
+		lockouts = append(lockouts, lockoutFromRow(row))
+	}
+
+	return lockouts, nil
+}
+
+func (r *LockoutsRepository) RegisterAttempt(ctx context.Context, userType model.UserType, name string, now, resetBefore time.Time, lockFor func(int) time.Duration) (*model.Lockout, bool, error) {
+	tx, err := r.connection.Begin(ctx)
+	if err != nil {
+		return nil, false, err
+	}
+	defer tx.Rollback(ctx)
+
+	txQuery := r.queries.WithTx(tx)
+	err = txQuery.CreateLockout(ctx, sqlc.CreateLockoutParams{
+		UserType:      string(userType),
+		Name:          name,
+		LastFailureAt: pgtype.Timestamptz{Time: now, Valid: true},
+	})
+	if err != nil {
+		return nil, false, err
+	}
+
+	// The row stays locked until commit, so concurrent attempts of the same
+	// name are counted one after another.
+	row, err := txQuery.FindLockoutForUpdate(ctx, sqlc.FindLockoutForUpdateParams{
+		UserType: string(userType),
+		Name:     name,
+	})
+	if err != nil {
+		return nil, false, err
+	}
+
+	lockout := lockoutFromRow(row)
+	if !lockout.Attempt(now, resetBefore, lockFor) {
+		return lockout, false, nil
+	}
+
+	err = txQuery.UpdateLockout(ctx, sqlc.UpdateLockoutParams{
+		UserType:      string(lockout.UserType),
+		Name:          lockout.Name,
+		Failures:      int32(lockout.Failures),
+		LockedUntil:   pgtype.Timestamptz{Time: lockout.LockedUntil, Valid: !lockout.LockedUntil.IsZero()},
+		LastFailureAt: pgtype.Timestamptz{Time: lockout.LastFailureAt, Valid: true},
+	})
+	if err != nil {
+		return nil, false, err
+	}
+
+	return lockout, true, tx.Commit(ctx)
+}
+
+func (r *LockoutsRepository) Delete(ctx context.Context, userType model.UserType, name string) error {
+	deleted, err := r.queries.DeleteLockout(ctx, sqlc.DeleteLockoutParams{
+		UserType: string(userType),
+		Name:     name,
+	})
+	if err != nil {
+		return err
+	}
+	if deleted == 0 {
+		return storage.ErrorModelNotFound
+	}
+
+	return nil
+}
+
+func lockoutFromRow(row sqlc.Lockout) *model.Lockout {
+	return &model.Lockout{
+		UserType:      model.UserType(row.UserType),
+		Name:          row.Name,
+		Failures:      int(row.Failures),
+		LockedUntil:   row.LockedUntil.Time,
+		LastFailureAt: row.LastFailureAt.Time,
+	}
+}
+
+// DeleteStale deletes up to limit lockouts that aren't locked at now and
+// whose last failure is before resetBefore, so they would be forgotten anyway.
+func (r *LockoutsRepository) DeleteStale(ctx context.Context, now, resetBefore time.Time, limit int) (int64, error) {
+	return r.queries.DeleteStaleLockouts(ctx, sqlc.DeleteStaleLockoutsParams{
+		ResetBefore: pgtype.Timestamptz{Time: resetBefore, Valid: true},
+		Now:         pgtype.Timestamptz{Time: now, Valid: true},
+		BatchSize:   int32(limit),
+	})
+}
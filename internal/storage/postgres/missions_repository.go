@@ -2,8 +2,10 @@ package postgres
 
 import (
 	"context"
+	"errors"
 
 	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/jackc/pgx/v5/pgtype"
 	"github.com/m1crogravity/spy-cat-agency/internal/model"
 	"github.com/m1crogravity/spy-cat-agency/internal/storage"
@@ -89,6 +91,7 @@ func (r *MissionsRepository) FindMissionById(ctx context.Context, id int64) (*mo
 	return &model.Mission{
 		Id:            row.MissionID,
 		State:         model.CompleteState(row.MissionState),
+		Reason:        row.MissionReason,
 		AssignedCatId: row.SpyCatID.Int64,
 		Targets:       targets,
 	}, nil
@@ -102,7 +105,28 @@ func (r *MissionsRepository) DeleteMission(ctx context.Context, id int64) error
 	return nil
 }
 
-func (r *MissionsRepository) SaveMission(ctx context.Context, mission *model.Mission) error {
+// SaveMission saves the mission moved out of the from state. A mission that
+// left that state in the meantime is an edit conflict.
+func (r *MissionsRepository) SaveMission(ctx context.Context, mission *model.Mission, from model.CompleteState) error {
+	tx, err := r.connection.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	err = saveMission(ctx, r.queries.WithTx(tx), mission, from)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+// SaveMissionHandoff saves the mission reassigned by the handoff and records
+// the handoff in the same transaction. The mission row is locked first, so a
+// mission that changed hands or left the from state in the meantime is an
+// edit conflict.
+func (r *MissionsRepository) SaveMissionHandoff(ctx context.Context, mission *model.Mission, from model.CompleteState, handoff *model.MissionHandoff) error {
 	tx, err := r.connection.Begin(ctx)
 	if err != nil {
 		return err
@@ -110,14 +134,88 @@ func (r *MissionsRepository) SaveMission(ctx context.Context, mission *model.Mis
 	defer tx.Rollback(ctx)
 
 	txQuery := r.queries.WithTx(tx)
-	err = txQuery.UpdateMission(ctx, sqlc.UpdateMissionParams{
-		ID:       mission.Id,
-		State:    string(mission.State),
-		SpyCatID: pgtype.Int8{Int64: mission.AssignedCatId, Valid: mission.AssignedCatId != 0},
+	spyCatId, err := txQuery.LockMission(ctx, mission.Id)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return storage.ErrorModelNotFound
+		}
+		return err
+	}
+	if spyCatId.Int64 != handoff.FromSpyCatId {
+		return storage.ErrorEditConflict
+	}
+
+	err = saveMission(ctx, txQuery, mission, from)
+	if err != nil {
+		return err
+	}
+
+	row, err := txQuery.CreateMissionHandoff(ctx, sqlc.CreateMissionHandoffParams{
+		MissionID:    handoff.MissionId,
+		FromSpyCatID: handoff.FromSpyCatId,
+		ToSpyCatID:   pgtype.Int8{Int64: handoff.ToSpyCatId, Valid: handoff.ToSpyCatId != 0},
+		AgentID:      handoff.AgentId,
+	})
+	if err != nil {
+		return err
+	}
+	handoff.Id = row.ID
+	handoff.CreatedAt = row.CreatedAt.Time
+
+	return tx.Commit(ctx)
+}
+
+func (r *MissionsRepository) FindMissionHandoffs(ctx context.Context, missionId int64) ([]*model.MissionHandoff, error) {
+	rows, err := r.queries.ListMissionHandoffs(ctx, missionId)
+	if err != nil {
+		return nil, err
+	}
+
+	handoffs := make([]*model.MissionHandoff, len(rows))
+	for i, row := range rows {
+		handoffs[i] = &model.MissionHandoff{
+			Id:           row.ID,
+			MissionId:    row.MissionID,
+			FromSpyCatId: row.FromSpyCatID,
+			ToSpyCatId:   row.ToSpyCatID.Int64,
+			AgentId:      row.AgentID,
+			CreatedAt:    row.CreatedAt.Time,
+		}
+	}
+	return handoffs, nil
+}
+
+// saveMission locks the spy cat of a mission left in progress before updating
+// the mission, so the spy cat can't be archived meanwhile. An archived spy cat
+// is storage.ErrorModelNotFound.
+func saveMission(ctx context.Context, txQuery *sqlc.Queries, mission *model.Mission, from model.CompleteState) error {
+	if mission.State == model.InProgress && mission.IsAssignedToCat() {
+		_, err := txQuery.LockSpyCat(ctx, mission.AssignedCatId)
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return storage.ErrorModelNotFound
+			}
+			return err
+		}
+	}
+
+	rows, err := txQuery.UpdateMission(ctx, sqlc.UpdateMissionParams{
+		ID:        mission.Id,
+		State:     string(mission.State),
+		SpyCatID:  pgtype.Int8{Int64: mission.AssignedCatId, Valid: mission.AssignedCatId != 0},
+		Reason:    mission.Reason,
+		FromState: string(from),
 	})
 	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+			return storage.ErrorUniqueConstraintViolation
+		}
 		return err
 	}
+	if rows == 0 {
+		return storage.ErrorEditConflict
+	}
 
 	insertTargets := []sqlc.CreateTargetsParams{}
 	for i, target := range mission.Targets {
@@ -143,11 +241,7 @@ func (r *MissionsRepository) SaveMission(ctx context.Context, mission *model.Mis
 	}
 
 	_, err = txQuery.CreateTargets(ctx, insertTargets)
-	if err != nil {
-		return err
-	}
-
-	return tx.Commit(ctx)
+	return err
 }
 
 func (r *MissionsRepository) SaveTarget(ctx context.Context, target *model.Target) error {
@@ -216,13 +310,20 @@ func (r *MissionsRepository) FindActiveMission(ctx context.Context, spyCatId int
 	return &model.Mission{
 		Id:            row.MissionID,
 		State:         model.CompleteState(row.MissionState),
+		Reason:        row.MissionReason,
 		AssignedCatId: row.SpyCatID.Int64,
 		Targets:       targets,
 	}, nil
 }
 
-func (r *MissionsRepository) FindAll(ctx context.Context) ([]*model.Mission, error) {
-	missionRows, err := r.queries.FindAllMissions(ctx)
+func (r *MissionsRepository) FindAll(ctx context.Context, filter model.MissionsFilter) ([]*model.Mission, error) {
+	missionRows, err := r.queries.ListMissions(ctx, sqlc.ListMissionsParams{
+		After:     filter.After,
+		State:     pgtype.Text{String: string(filter.State), Valid: filter.State != ""},
+		SpyCatID:  pgtype.Int8{Int64: filter.SpyCatId, Valid: filter.SpyCatId != 0},
+		Country:   pgtype.Text{String: filter.Country, Valid: filter.Country != ""},
+		PageLimit: int32(filter.Limit),
+	})
 	if err != nil {
 		return nil, err
 	}
@@ -235,6 +336,7 @@ func (r *MissionsRepository) FindAll(ctx context.Context) ([]*model.Mission, err
 			currentMission = &model.Mission{
 				Id:            missionRow.MissionID,
 				State:         model.CompleteState(missionRow.MissionState),
+				Reason:        missionRow.MissionReason,
 				AssignedCatId: missionRow.SpyCatID.Int64,
 				Targets:       make([]*model.Target, 0),
 			}
@@ -261,3 +363,17 @@ func updateTarget(ctx context.Context, queries *sqlc.Queries, target *model.Targ
 		State:     string(target.State),
 	})
 }
+
+func (r *MissionsRepository) CountByState(ctx context.Context) (map[model.CompleteState]int, error) {
+	rows, err := r.queries.CountMissionsByState(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	counts := make(map[model.CompleteState]int, len(rows))
+	for _, row := range rows {
+		counts[model.CompleteState(row.State)] = int(row.Count)
+	}
+
+	return counts, nil
+}
@@ -1,40 +1,49 @@
 package postgres
 
 import (
+	"cmp"
 	"context"
 	"errors"
+	"time"
 
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgtype"
 	"github.com/m1crogravity/spy-cat-agency/internal/model"
 	"github.com/m1crogravity/spy-cat-agency/internal/storage"
 	"github.com/m1crogravity/spy-cat-agency/internal/storage/postgres/sqlc"
 )
 
 type SpyCatsRepository struct {
-	queries *sqlc.Queries
+	queries    *sqlc.Queries
+	connection Connection
 }
 
-func NewSpyCatsRepository(conn sqlc.DBTX) *SpyCatsRepository {
+func NewSpyCatsRepository(conn Connection) *SpyCatsRepository {
 	return &SpyCatsRepository{
-		queries: sqlc.New(conn),
+		queries:    sqlc.New(conn),
+		connection: conn,
 	}
 }
 
 func (r *SpyCatsRepository) Create(ctx context.Context, spyCat *model.SpyCat) error {
-	id, err := r.queries.CreateSpyCat(ctx, sqlc.CreateSpyCatParams{
+	row, err := r.queries.CreateSpyCat(ctx, sqlc.CreateSpyCatParams{
 		Name:              spyCat.Name,
 		PasswordHash:      spyCat.Password.Hash,
 		YearsOfExperience: int32(spyCat.YearsOfExperience),
 		Breed:             spyCat.Breed,
-		Salary:            spyCat.Salary,
+		Salary:            spyCat.Salary.Amount,
+		SalaryCurrency:    spyCat.Salary.Currency,
 	})
 	if err != nil {
 		var pgErr *pgconn.PgError
 		if errors.As(err, &pgErr) && pgErr.Code == "23505" {
 			return storage.ErrorUniqueConstraintViolation
 		}
+		return err
 	}
-	spyCat.Id = id
+	spyCat.Id = row.ID
+	spyCat.CreatedAt = row.CreatedAt.Time
 	return nil
 }
 
@@ -47,32 +56,192 @@ func (r *SpyCatsRepository) FindById(ctx context.Context, id int64) (*model.SpyC
 	return convert(spyCat), nil
 }
 
+// Delete archives the spy cat unless it has a mission in progress, in which
+// case storage.ErrorEditConflict is returned.
+// Delete archives the spy cat unless it has a mission in progress, then
+// storage.ErrorEditConflict is returned. The spy cat row is locked first, the
+// way a mission assigned to it locks it, so the archive sees every mission
+// assigned before.
 func (r *SpyCatsRepository) Delete(ctx context.Context, id int64) error {
-	err := r.queries.DeleteSpyCatById(ctx, id)
+	tx, err := r.connection.Begin(ctx)
 	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	txQuery := r.queries.WithTx(tx)
+	_, err = txQuery.LockSpyCat(ctx, id)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return storage.ErrorModelNotFound
+		}
+		return err
+	}
+
+	rows, err := txQuery.ArchiveSpyCat(ctx, id)
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return storage.ErrorEditConflict
+	}
+
+	return tx.Commit(ctx)
+}
+
+func (r *SpyCatsRepository) Restore(ctx context.Context, id int64) error {
+	rows, err := r.queries.RestoreSpyCat(ctx, id)
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
 		return storage.ErrorModelNotFound
 	}
 	return nil
 }
 
-func (r *SpyCatsRepository) Save(ctx context.Context, spyCat model.SpyCat) error {
-	return r.queries.UpdateSpyCat(ctx, sqlc.UpdateSpyCatParams{
-		ID:     spyCat.Id,
-		Salary: spyCat.Salary,
+// Save updates the spy cat and, when given, records the salary change in the
+// same transaction. The spy cat is only updated while its salary is still the
+// old salary of the change, or its own salary without a change, otherwise
+// storage.ErrorEditConflict is returned. A change effective before the latest
+// recorded one is rejected with model.ErrBackdatedSalaryChange.
+func (r *SpyCatsRepository) Save(ctx context.Context, spyCat model.SpyCat, change *model.SalaryChange) error {
+	tx, err := r.connection.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	oldSalary := spyCat.Salary
+	if change != nil {
+		oldSalary = change.OldSalary
+	}
+
+	txQuery := r.queries.WithTx(tx)
+	rows, err := txQuery.UpdateSpyCat(ctx, sqlc.UpdateSpyCatParams{
+		Name:              spyCat.Name,
+		YearsOfExperience: int32(spyCat.YearsOfExperience),
+		Breed:             spyCat.Breed,
+		Salary:            spyCat.Salary.Amount,
+		SalaryCurrency:    spyCat.Salary.Currency,
+		ID:                spyCat.Id,
+		OldSalary:         oldSalary.Amount,
+		OldSalaryCurrency: oldSalary.Currency,
 	})
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+			return storage.ErrorUniqueConstraintViolation
+		}
+		return err
+	}
+	if rows == 0 {
+		return storage.ErrorEditConflict
+	}
+
+	if change != nil {
+		// The update above locks the spy cat row, so no other change of the
+		// spy cat can be recorded until the transaction ends.
+		backdated, err := txQuery.SalaryChangeExistsAfter(ctx, sqlc.SalaryChangeExistsAfterParams{
+			SpyCatID:      change.SpyCatId,
+			EffectiveDate: pgtype.Date{Time: change.EffectiveDate, Valid: true},
+		})
+		if err != nil {
+			return err
+		}
+		if backdated {
+			return model.ErrBackdatedSalaryChange
+		}
+
+		row, err := txQuery.CreateSalaryChange(ctx, sqlc.CreateSalaryChangeParams{
+			SpyCatID:          change.SpyCatId,
+			AgentID:           change.AgentId,
+			OldSalary:         change.OldSalary.Amount,
+			OldSalaryCurrency: change.OldSalary.Currency,
+			NewSalary:         change.NewSalary.Amount,
+			NewSalaryCurrency: change.NewSalary.Currency,
+			EffectiveDate:     pgtype.Date{Time: change.EffectiveDate, Valid: true},
+			Reason:            change.Reason,
+		})
+		if err != nil {
+			return err
+		}
+		change.Id = row.ID
+		change.CreatedAt = row.CreatedAt.Time
+	}
+
+	return tx.Commit(ctx)
 }
 
-func (r *SpyCatsRepository) FindAll(ctx context.Context) ([]*model.SpyCat, error) {
-	sc, err := r.queries.ListSpyCats(ctx)
+func (r *SpyCatsRepository) FindSalaryChanges(ctx context.Context, spyCatId int64) ([]*model.SalaryChange, error) {
+	rows, err := r.queries.ListSalaryChangesBySpyCat(ctx, spyCatId)
 	if err != nil {
 		return nil, err
 	}
 
-	spyCats := make([]*model.SpyCat, len(sc))
-	for i, spyCat := range sc {
-		spyCats[i] = convert(spyCat)
+	return convertSalaryChanges(rows), nil
+}
+
+func (r *SpyCatsRepository) FindSalaryChangesBetween(ctx context.Context, after, until time.Time) ([]*model.SalaryChange, error) {
+	rows, err := r.queries.ListSalaryChangesBetween(ctx, sqlc.ListSalaryChangesBetweenParams{
+		After: pgtype.Date{Time: after, Valid: true},
+		Until: pgtype.Date{Time: until, Valid: true},
+	})
+	if err != nil {
+		return nil, err
 	}
-	return spyCats, nil
+
+	return convertSalaryChanges(rows), nil
+}
+
+func (r *SpyCatsRepository) FindAll(ctx context.Context, filter model.SpyCatsFilter) ([]*model.SpyCat, int, error) {
+	params := sqlc.CountSpyCatsParams{
+		Breed:  pgtype.Text{String: filter.Breed, Valid: filter.Breed != ""},
+		Status: cmp.Or(filter.Status, model.SpyCatStatusActive),
+	}
+	if filter.MinExperience != nil {
+		params.MinExperience = pgtype.Int4{Int32: int32(*filter.MinExperience), Valid: true}
+	}
+	if filter.MaxExperience != nil {
+		params.MaxExperience = pgtype.Int4{Int32: int32(*filter.MaxExperience), Valid: true}
+	}
+	if filter.MinSalary != nil {
+		params.SalaryCurrency = pgtype.Text{String: filter.MinSalary.Currency, Valid: true}
+		params.MinSalary = pgtype.Int8{Int64: filter.MinSalary.Amount, Valid: true}
+	}
+	if filter.MaxSalary != nil {
+		params.SalaryCurrency = pgtype.Text{String: filter.MaxSalary.Currency, Valid: true}
+		params.MaxSalary = pgtype.Int8{Int64: filter.MaxSalary.Amount, Valid: true}
+	}
+
+	// The total is counted separately so that a page past the end still
+	// reports it.
+	totalRecords, err := r.queries.CountSpyCats(ctx, params)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	rows, err := r.queries.ListSpyCats(ctx, sqlc.ListSpyCatsParams{
+		Breed:          params.Breed,
+		MinExperience:  params.MinExperience,
+		MaxExperience:  params.MaxExperience,
+		SalaryCurrency: params.SalaryCurrency,
+		MinSalary:      params.MinSalary,
+		MaxSalary:      params.MaxSalary,
+		Status:         params.Status,
+		Sort:           filter.Sort,
+		PageLimit:      int32(filter.Limit()),
+		PageOffset:     int32(filter.Offset()),
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+
+	spyCats := make([]*model.SpyCat, len(rows))
+	for i, row := range rows {
+		spyCats[i] = convert(row)
+	}
+	return spyCats, int(totalRecords), nil
 }
 
 func (r *SpyCatsRepository) FindByName(ctx context.Context, name string) (*model.SpyCat, error) {
@@ -85,12 +254,48 @@ func (r *SpyCatsRepository) FindByName(ctx context.Context, name string) (*model
 }
 
 func convert(spyCat sqlc.SpyCat) *model.SpyCat {
-	return &model.SpyCat{
+	converted := &model.SpyCat{
 		Id:                spyCat.ID,
 		Name:              spyCat.Name,
 		Password:          model.Password{Hash: spyCat.PasswordHash},
 		YearsOfExperience: int(spyCat.YearsOfExperience),
 		Breed:             spyCat.Breed,
-		Salary:            spyCat.Salary,
+		Salary:            model.Money{Amount: spyCat.Salary, Currency: spyCat.SalaryCurrency},
+		CreatedAt:         spyCat.CreatedAt.Time,
+	}
+	if spyCat.DeletedAt.Valid {
+		converted.DeletedAt = &spyCat.DeletedAt.Time
+	}
+	return converted
+}
+
+func convertSalaryChanges(rows []sqlc.SalaryChange) []*model.SalaryChange {
+	changes := make([]*model.SalaryChange, len(rows))
+	for i, row := range rows {
+		changes[i] = &model.SalaryChange{
+			Id:            row.ID,
+			SpyCatId:      row.SpyCatID,
+			AgentId:       row.AgentID,
+			OldSalary:     model.Money{Amount: row.OldSalary, Currency: row.OldSalaryCurrency},
+			NewSalary:     model.Money{Amount: row.NewSalary, Currency: row.NewSalaryCurrency},
+			EffectiveDate: row.EffectiveDate.Time,
+			Reason:        row.Reason,
+			CreatedAt:     row.CreatedAt.Time,
+		}
 	}
+	return changes
+}
+
+func (r *SpyCatsRepository) UpdatePassword(ctx context.Context, spyCat *model.SpyCat) error {
+	rows, err := r.queries.UpdateSpyCatPassword(ctx, sqlc.UpdateSpyCatPasswordParams{
+		ID:           spyCat.Id,
+		PasswordHash: spyCat.Password.Hash,
+	})
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return storage.ErrorModelNotFound
+	}
+	return nil
 }
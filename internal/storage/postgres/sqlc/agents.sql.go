@@ -9,11 +9,23 @@ import (
 	"context"
 )
 
+const countAgents = `-- name: CountAgents :one
+SELECT count(*)
+FROM agents
+`
+
+func (q *Queries) CountAgents(ctx context.Context) (int64, error) {
+	row := q.db.QueryRow(ctx, countAgents)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
 const createAgent = `-- name: CreateAgent :one
 INSERT INTO agents (
-  name, password_hash
+  name, password_hash, role
 ) VALUES (
-  $1, $2
+  $1, $2, $3
 )
 RETURNING id
 `
@@ -21,17 +33,18 @@ RETURNING id
 type CreateAgentParams struct {
 	Name         string
 	PasswordHash []byte
+	Role         string
 }
 
 func (q *Queries) CreateAgent(ctx context.Context, arg CreateAgentParams) (int64, error) {
-	row := q.db.QueryRow(ctx, createAgent, arg.Name, arg.PasswordHash)
+	row := q.db.QueryRow(ctx, createAgent, arg.Name, arg.PasswordHash, arg.Role)
 	var id int64
 	err := row.Scan(&id)
 	return id, err
 }
 
 const findAgentById = `-- name: FindAgentById :one
-SELECT id, name, password_hash
+SELECT id, name, password_hash, role
 FROM agents
 WHERE id = $1
 LIMIT 1
@@ -40,12 +53,17 @@ LIMIT 1
 func (q *Queries) FindAgentById(ctx context.Context, id int64) (Agent, error) {
 	row := q.db.QueryRow(ctx, findAgentById, id)
 	var i Agent
-	err := row.Scan(&i.ID, &i.Name, &i.PasswordHash)
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.PasswordHash,
+		&i.Role,
+	)
 	return i, err
 }
 
 const findAgentByName = `-- name: FindAgentByName :one
-SELECT id, name, password_hash
+SELECT id, name, password_hash, role
 FROM agents
 WHERE name = $1
 LIMIT 1
@@ -54,6 +72,55 @@ LIMIT 1
 func (q *Queries) FindAgentByName(ctx context.Context, name string) (Agent, error) {
 	row := q.db.QueryRow(ctx, findAgentByName, name)
 	var i Agent
-	err := row.Scan(&i.ID, &i.Name, &i.PasswordHash)
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.PasswordHash,
+		&i.Role,
+	)
 	return i, err
 }
+
+const lockAgents = `-- name: LockAgents :exec
+LOCK TABLE agents IN SHARE ROW EXCLUSIVE MODE
+`
+
+func (q *Queries) LockAgents(ctx context.Context) error {
+	_, err := q.db.Exec(ctx, lockAgents)
+	return err
+}
+
+const updateAgentPassword = `-- name: UpdateAgentPassword :execrows
+UPDATE agents
+SET password_hash = $2
+WHERE id = $1
+`
+
+type UpdateAgentPasswordParams struct {
+	ID           int64
+	PasswordHash []byte
+}
+
+func (q *Queries) UpdateAgentPassword(ctx context.Context, arg UpdateAgentPasswordParams) (int64, error) {
+	result, err := q.db.Exec(ctx, updateAgentPassword, arg.ID, arg.PasswordHash)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const updateAgentRole = `-- name: UpdateAgentRole :exec
+UPDATE agents
+SET role = $2
+WHERE id = $1
+`
+
+type UpdateAgentRoleParams struct {
+	ID   int64
+	Role string
+}
+
+func (q *Queries) UpdateAgentRole(ctx context.Context, arg UpdateAgentRoleParams) error {
+	_, err := q.db.Exec(ctx, updateAgentRole, arg.ID, arg.Role)
+	return err
+}
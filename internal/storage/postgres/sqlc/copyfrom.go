@@ -9,6 +9,42 @@ import (
 	"context"
 )
 
+// iteratorForCreateBreeds implements pgx.CopyFromSource.
+type iteratorForCreateBreeds struct {
+	rows                 []CreateBreedsParams
+	skippedFirstNextCall bool
+}
+
+func (r *iteratorForCreateBreeds) Next() bool {
+	if len(r.rows) == 0 {
+		return false
+	}
+	if !r.skippedFirstNextCall {
+		r.skippedFirstNextCall = true
+		return true
+	}
+	r.rows = r.rows[1:]
+	return len(r.rows) > 0
+}
+
+func (r iteratorForCreateBreeds) Values() ([]interface{}, error) {
+	return []interface{}{
+		r.rows[0].Name,
+		r.rows[0].ID,
+		r.rows[0].Origin,
+		r.rows[0].Temperament,
+		r.rows[0].LifeSpan,
+	}, nil
+}
+
+func (r iteratorForCreateBreeds) Err() error {
+	return nil
+}
+
+func (q *Queries) CreateBreeds(ctx context.Context, arg []CreateBreedsParams) (int64, error) {
+	return q.db.CopyFrom(ctx, []string{"breeds"}, []string{"name", "id", "origin", "temperament", "life_span"}, &iteratorForCreateBreeds{rows: arg})
+}
+
 // iteratorForCreateTargets implements pgx.CopyFromSource.
 type iteratorForCreateTargets struct {
 	rows                 []CreateTargetsParams
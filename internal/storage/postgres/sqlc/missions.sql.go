@@ -11,6 +11,37 @@ import (
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
+const countMissionsByState = `-- name: CountMissionsByState :many
+SELECT state, count(*) AS count
+FROM missions
+GROUP BY state
+`
+
+type CountMissionsByStateRow struct {
+	State string
+	Count int64
+}
+
+func (q *Queries) CountMissionsByState(ctx context.Context) ([]CountMissionsByStateRow, error) {
+	rows, err := q.db.Query(ctx, countMissionsByState)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []CountMissionsByStateRow
+	for rows.Next() {
+		var i CountMissionsByStateRow
+		if err := rows.Scan(&i.State, &i.Count); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const createMission = `-- name: CreateMission :one
 INSERT INTO missions (
   state
@@ -66,6 +97,7 @@ func (q *Queries) DeleteTarget(ctx context.Context, arg DeleteTargetParams) erro
 const findActiveMission = `-- name: FindActiveMission :many
 SELECT missions.id as mission_id,
   missions.state as mission_state,
+  missions.reason as mission_reason,
   missions.spy_cat_id,
   targets.id as target_id,
   targets.name,
@@ -79,14 +111,15 @@ WHERE missions.spy_cat_id = $1
 `
 
 type FindActiveMissionRow struct {
-	MissionID    int64
-	MissionState string
-	SpyCatID     pgtype.Int8
-	TargetID     int64
-	Name         string
-	Country      string
-	Notes        string
-	TargetState  string
+	MissionID     int64
+	MissionState  string
+	MissionReason string
+	SpyCatID      pgtype.Int8
+	TargetID      int64
+	Name          string
+	Country       string
+	Notes         string
+	TargetState   string
 }
 
 func (q *Queries) FindActiveMission(ctx context.Context, spyCatID pgtype.Int8) ([]FindActiveMissionRow, error) {
@@ -101,6 +134,7 @@ func (q *Queries) FindActiveMission(ctx context.Context, spyCatID pgtype.Int8) (
 		if err := rows.Scan(
 			&i.MissionID,
 			&i.MissionState,
+			&i.MissionReason,
 			&i.SpyCatID,
 			&i.TargetID,
 			&i.Name,
@@ -118,9 +152,10 @@ func (q *Queries) FindActiveMission(ctx context.Context, spyCatID pgtype.Int8) (
 	return items, nil
 }
 
-const findAllMissions = `-- name: FindAllMissions :many
+const findMissionById = `-- name: FindMissionById :many
 SELECT missions.id as mission_id,
   missions.state as mission_state,
+  missions.reason as mission_reason,
   missions.spy_cat_id,
   targets.id as target_id,
   targets.name,
@@ -129,32 +164,34 @@ SELECT missions.id as mission_id,
   targets.state as target_state
 FROM missions
 INNER JOIN targets ON missions.id = targets.mission_id
-ORDER BY missions.id ASC
+WHERE missions.id = $1
 `
 
-type FindAllMissionsRow struct {
-	MissionID    int64
-	MissionState string
-	SpyCatID     pgtype.Int8
-	TargetID     int64
-	Name         string
-	Country      string
-	Notes        string
-	TargetState  string
+type FindMissionByIdRow struct {
+	MissionID     int64
+	MissionState  string
+	MissionReason string
+	SpyCatID      pgtype.Int8
+	TargetID      int64
+	Name          string
+	Country       string
+	Notes         string
+	TargetState   string
 }
 
-func (q *Queries) FindAllMissions(ctx context.Context) ([]FindAllMissionsRow, error) {
-	rows, err := q.db.Query(ctx, findAllMissions)
+func (q *Queries) FindMissionById(ctx context.Context, id int64) ([]FindMissionByIdRow, error) {
+	rows, err := q.db.Query(ctx, findMissionById, id)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []FindAllMissionsRow
+	var items []FindMissionByIdRow
 	for rows.Next() {
-		var i FindAllMissionsRow
+		var i FindMissionByIdRow
 		if err := rows.Scan(
 			&i.MissionID,
 			&i.MissionState,
+			&i.MissionReason,
 			&i.SpyCatID,
 			&i.TargetID,
 			&i.Name,
@@ -172,9 +209,10 @@ func (q *Queries) FindAllMissions(ctx context.Context) ([]FindAllMissionsRow, er
 	return items, nil
 }
 
-const findMissionById = `-- name: FindMissionById :many
+const listMissions = `-- name: ListMissions :many
 SELECT missions.id as mission_id,
   missions.state as mission_state,
+  missions.reason as mission_reason,
   missions.spy_cat_id,
   targets.id as target_id,
   targets.name,
@@ -183,32 +221,63 @@ SELECT missions.id as mission_id,
   targets.state as target_state
 FROM missions
 INNER JOIN targets ON missions.id = targets.mission_id
-WHERE missions.id = $1
+WHERE missions.id IN (
+  SELECT m.id
+  FROM missions m
+  WHERE m.id > $1
+    AND ($2::text IS NULL OR m.state = $2)
+    AND ($3::bigint IS NULL OR m.spy_cat_id = $3)
+    AND ($4::text IS NULL OR EXISTS (
+      SELECT 1
+      FROM targets t
+      WHERE t.mission_id = m.id
+        AND lower(t.country) = lower($4)
+    ))
+  ORDER BY m.id ASC
+  LIMIT $5
+)
+ORDER BY missions.id ASC, targets.id ASC
 `
 
-type FindMissionByIdRow struct {
-	MissionID    int64
-	MissionState string
-	SpyCatID     pgtype.Int8
-	TargetID     int64
-	Name         string
-	Country      string
-	Notes        string
-	TargetState  string
+type ListMissionsParams struct {
+	After     int64
+	State     pgtype.Text
+	SpyCatID  pgtype.Int8
+	Country   pgtype.Text
+	PageLimit int32
 }
 
-func (q *Queries) FindMissionById(ctx context.Context, id int64) ([]FindMissionByIdRow, error) {
-	rows, err := q.db.Query(ctx, findMissionById, id)
+type ListMissionsRow struct {
+	MissionID     int64
+	MissionState  string
+	MissionReason string
+	SpyCatID      pgtype.Int8
+	TargetID      int64
+	Name          string
+	Country       string
+	Notes         string
+	TargetState   string
+}
+
+func (q *Queries) ListMissions(ctx context.Context, arg ListMissionsParams) ([]ListMissionsRow, error) {
+	rows, err := q.db.Query(ctx, listMissions,
+		arg.After,
+		arg.State,
+		arg.SpyCatID,
+		arg.Country,
+		arg.PageLimit,
+	)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []FindMissionByIdRow
+	var items []ListMissionsRow
 	for rows.Next() {
-		var i FindMissionByIdRow
+		var i ListMissionsRow
 		if err := rows.Scan(
 			&i.MissionID,
 			&i.MissionState,
+			&i.MissionReason,
 			&i.SpyCatID,
 			&i.TargetID,
 			&i.Name,
@@ -226,22 +295,49 @@ func (q *Queries) FindMissionById(ctx context.Context, id int64) ([]FindMissionB
 	return items, nil
 }
 
-const updateMission = `-- name: UpdateMission :exec
-UPDATE missions
-SET state = $2,
-  spy_cat_id = $3
+const lockMission = `-- name: LockMission :one
+SELECT spy_cat_id
+FROM missions
 WHERE id = $1
+FOR UPDATE
+`
+
+func (q *Queries) LockMission(ctx context.Context, id int64) (pgtype.Int8, error) {
+	row := q.db.QueryRow(ctx, lockMission, id)
+	var spy_cat_id pgtype.Int8
+	err := row.Scan(&spy_cat_id)
+	return spy_cat_id, err
+}
+
+const updateMission = `-- name: UpdateMission :execrows
+UPDATE missions
+SET state = $1,
+  spy_cat_id = $2,
+  reason = $3
+WHERE id = $4
+  AND state = $5
 `
 
 type UpdateMissionParams struct {
-	ID       int64
-	State    string
-	SpyCatID pgtype.Int8
+	State     string
+	SpyCatID  pgtype.Int8
+	Reason    string
+	ID        int64
+	FromState string
 }
 
-func (q *Queries) UpdateMission(ctx context.Context, arg UpdateMissionParams) error {
-	_, err := q.db.Exec(ctx, updateMission, arg.ID, arg.State, arg.SpyCatID)
-	return err
+func (q *Queries) UpdateMission(ctx context.Context, arg UpdateMissionParams) (int64, error) {
+	result, err := q.db.Exec(ctx, updateMission,
+		arg.State,
+		arg.SpyCatID,
+		arg.Reason,
+		arg.ID,
+		arg.FromState,
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
 }
 
 const updateTarget = `-- name: UpdateTarget :exec
@@ -12,12 +12,52 @@ type Agent struct {
 	ID           int64
 	Name         string
 	PasswordHash []byte
+	Role         string
+}
+
+type Breed struct {
+	Name        string
+	ID          string
+	Origin      string
+	Temperament string
+	LifeSpan    string
+}
+
+type Lockout struct {
+	UserType      string
+	Name          string
+	Failures      int32
+	LockedUntil   pgtype.Timestamptz
+	LastFailureAt pgtype.Timestamptz
 }
 
 type Mission struct {
 	ID       int64
 	State    string
 	SpyCatID pgtype.Int8
+	Reason   string
+}
+
+type MissionHandoff struct {
+	ID           int64
+	MissionID    int64
+	FromSpyCatID int64
+	ToSpyCatID   pgtype.Int8
+	AgentID      int64
+	CreatedAt    pgtype.Timestamptz
+}
+
+type SalaryChange struct {
+	ID                int64
+	SpyCatID          int64
+	AgentID           int64
+	OldSalary         int64
+	NewSalary         int64
+	EffectiveDate     pgtype.Date
+	Reason            string
+	CreatedAt         pgtype.Timestamptz
+	OldSalaryCurrency string
+	NewSalaryCurrency string
 }
 
 type SpyCat struct {
@@ -26,7 +66,10 @@ type SpyCat struct {
 	PasswordHash      []byte
 	YearsOfExperience int32
 	Breed             string
-	Salary            float64
+	Salary            int64
+	SalaryCurrency    string
+	DeletedAt         pgtype.Timestamptz
+	CreatedAt         pgtype.Timestamptz
 }
 
 type Target struct {
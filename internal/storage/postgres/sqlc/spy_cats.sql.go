@@ -7,19 +7,80 @@ package sqlc
 
 import (
 	"context"
+
+	"github.com/jackc/pgx/v5/pgtype"
 )
 
+const archiveSpyCat = `-- name: ArchiveSpyCat :execrows
+UPDATE spy_cats
+SET deleted_at = NOW()
+WHERE id = $1
+  AND deleted_at IS NULL
+  AND NOT EXISTS (
+    SELECT 1
+    FROM missions
+    WHERE spy_cat_id = $1
+      AND state = 'in_progress'
+  )
+`
+
+func (q *Queries) ArchiveSpyCat(ctx context.Context, id int64) (int64, error) {
+	result, err := q.db.Exec(ctx, archiveSpyCat, id)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const countSpyCats = `-- name: CountSpyCats :one
+SELECT count(*)
+FROM spy_cats
+WHERE ($1::text IS NULL OR lower(breed) = lower($1))
+  AND ($2::integer IS NULL OR years_of_experience >= $2)
+  AND ($3::integer IS NULL OR years_of_experience <= $3)
+  AND ($4::text IS NULL OR salary_currency = $4)
+  AND ($5::bigint IS NULL OR salary >= $5)
+  AND ($6::bigint IS NULL OR salary <= $6)
+  AND ($7::text = 'all' OR ($7::text = 'archived') = (deleted_at IS NOT NULL))
+`
+
+type CountSpyCatsParams struct {
+	Breed          pgtype.Text
+	MinExperience  pgtype.Int4
+	MaxExperience  pgtype.Int4
+	SalaryCurrency pgtype.Text
+	MinSalary      pgtype.Int8
+	MaxSalary      pgtype.Int8
+	Status         string
+}
+
+func (q *Queries) CountSpyCats(ctx context.Context, arg CountSpyCatsParams) (int64, error) {
+	row := q.db.QueryRow(ctx, countSpyCats,
+		arg.Breed,
+		arg.MinExperience,
+		arg.MaxExperience,
+		arg.SalaryCurrency,
+		arg.MinSalary,
+		arg.MaxSalary,
+		arg.Status,
+	)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
 const createSpyCat = `-- name: CreateSpyCat :one
 INSERT INTO spy_cats (
   name,
   password_hash,
   years_of_experience,
   breed,
-  salary
+  salary,
+  salary_currency
 ) VALUES (
-  $1, $2, $3, $4, $5
+  $1, $2, $3, $4, $5, $6
 )
-RETURNING id
+RETURNING id, created_at
 `
 
 type CreateSpyCatParams struct {
@@ -27,35 +88,31 @@ type CreateSpyCatParams struct {
 	PasswordHash      []byte
 	YearsOfExperience int32
 	Breed             string
-	Salary            float64
+	Salary            int64
+	SalaryCurrency    string
+}
+
+type CreateSpyCatRow struct {
+	ID        int64
+	CreatedAt pgtype.Timestamptz
 }
 
-func (q *Queries) CreateSpyCat(ctx context.Context, arg CreateSpyCatParams) (int64, error) {
+func (q *Queries) CreateSpyCat(ctx context.Context, arg CreateSpyCatParams) (CreateSpyCatRow, error) {
 	row := q.db.QueryRow(ctx, createSpyCat,
 		arg.Name,
 		arg.PasswordHash,
 		arg.YearsOfExperience,
 		arg.Breed,
 		arg.Salary,
+		arg.SalaryCurrency,
 	)
-	var id int64
-	err := row.Scan(&id)
-	return id, err
-}
-
-const deleteSpyCatById = `-- name: DeleteSpyCatById :exec
-DELETE
-FROM spy_cats
-WHERE id = $1
-`
-
-func (q *Queries) DeleteSpyCatById(ctx context.Context, id int64) error {
-	_, err := q.db.Exec(ctx, deleteSpyCatById, id)
-	return err
+	var i CreateSpyCatRow
+	err := row.Scan(&i.ID, &i.CreatedAt)
+	return i, err
 }
 
 const findSpyCatById = `-- name: FindSpyCatById :one
-SELECT id, name, password_hash, years_of_experience, breed, salary
+SELECT id, name, password_hash, years_of_experience, breed, salary, salary_currency, deleted_at, created_at
 FROM spy_cats
 WHERE id = $1
 LIMIT 1
@@ -71,12 +128,15 @@ func (q *Queries) FindSpyCatById(ctx context.Context, id int64) (SpyCat, error)
 		&i.YearsOfExperience,
 		&i.Breed,
 		&i.Salary,
+		&i.SalaryCurrency,
+		&i.DeletedAt,
+		&i.CreatedAt,
 	)
 	return i, err
 }
 
 const findSpyCatByName = `-- name: FindSpyCatByName :one
-SELECT id, name, password_hash, years_of_experience, breed, salary
+SELECT id, name, password_hash, years_of_experience, breed, salary, salary_currency, deleted_at, created_at
 FROM spy_cats
 WHERE name = $1
 LIMIT 1
@@ -92,18 +152,62 @@ func (q *Queries) FindSpyCatByName(ctx context.Context, name string) (SpyCat, er
 		&i.YearsOfExperience,
 		&i.Breed,
 		&i.Salary,
+		&i.SalaryCurrency,
+		&i.DeletedAt,
+		&i.CreatedAt,
 	)
 	return i, err
 }
 
 const listSpyCats = `-- name: ListSpyCats :many
-SELECT id, name, password_hash, years_of_experience, breed, salary
+SELECT id, name, password_hash, years_of_experience, breed, salary, salary_currency, deleted_at, created_at
 FROM spy_cats
-ORDER BY id ASC
+WHERE ($1::text IS NULL OR lower(breed) = lower($1))
+  AND ($2::integer IS NULL OR years_of_experience >= $2)
+  AND ($3::integer IS NULL OR years_of_experience <= $3)
+  AND ($4::text IS NULL OR salary_currency = $4)
+  AND ($5::bigint IS NULL OR salary >= $5)
+  AND ($6::bigint IS NULL OR salary <= $6)
+  AND ($7::text = 'all' OR ($7::text = 'archived') = (deleted_at IS NOT NULL))
+ORDER BY
+  CASE WHEN $8::text = 'name' THEN name END ASC,
+  CASE WHEN $8::text = '-name' THEN name END DESC,
+  CASE WHEN $8::text = 'years_of_experience' THEN years_of_experience END ASC,
+  CASE WHEN $8::text = '-years_of_experience' THEN years_of_experience END DESC,
+  CASE WHEN $8::text IN ('salary', '-salary') THEN salary_currency END ASC,
+  CASE WHEN $8::text = 'salary' THEN salary END ASC,
+  CASE WHEN $8::text = '-salary' THEN salary END DESC,
+  CASE WHEN $8::text = '-id' THEN id END DESC,
+  id ASC
+LIMIT $9 OFFSET $10
 `
 
-func (q *Queries) ListSpyCats(ctx context.Context) ([]SpyCat, error) {
-	rows, err := q.db.Query(ctx, listSpyCats)
+type ListSpyCatsParams struct {
+	Breed          pgtype.Text
+	MinExperience  pgtype.Int4
+	MaxExperience  pgtype.Int4
+	SalaryCurrency pgtype.Text
+	MinSalary      pgtype.Int8
+	MaxSalary      pgtype.Int8
+	Status         string
+	Sort           string
+	PageLimit      int32
+	PageOffset     int32
+}
+
+func (q *Queries) ListSpyCats(ctx context.Context, arg ListSpyCatsParams) ([]SpyCat, error) {
+	rows, err := q.db.Query(ctx, listSpyCats,
+		arg.Breed,
+		arg.MinExperience,
+		arg.MaxExperience,
+		arg.SalaryCurrency,
+		arg.MinSalary,
+		arg.MaxSalary,
+		arg.Status,
+		arg.Sort,
+		arg.PageLimit,
+		arg.PageOffset,
+	)
 	if err != nil {
 		return nil, err
 	}
@@ -118,6 +222,9 @@ func (q *Queries) ListSpyCats(ctx context.Context) ([]SpyCat, error) {
 			&i.YearsOfExperience,
 			&i.Breed,
 			&i.Salary,
+			&i.SalaryCurrency,
+			&i.DeletedAt,
+			&i.CreatedAt,
 		); err != nil {
 			return nil, err
 		}
@@ -129,18 +236,91 @@ func (q *Queries) ListSpyCats(ctx context.Context) ([]SpyCat, error) {
 	return items, nil
 }
 
-const updateSpyCat = `-- name: UpdateSpyCat :exec
+const lockSpyCat = `-- name: LockSpyCat :one
+SELECT id
+FROM spy_cats
+WHERE id = $1
+  AND deleted_at IS NULL
+FOR UPDATE
+`
+
+func (q *Queries) LockSpyCat(ctx context.Context, id int64) (int64, error) {
+	row := q.db.QueryRow(ctx, lockSpyCat, id)
+	err := row.Scan(&id)
+	return id, err
+}
+
+const restoreSpyCat = `-- name: RestoreSpyCat :execrows
 UPDATE spy_cats
-SET salary = $2
+SET deleted_at = NULL
 WHERE id = $1
+  AND deleted_at IS NOT NULL
+`
+
+func (q *Queries) RestoreSpyCat(ctx context.Context, id int64) (int64, error) {
+	result, err := q.db.Exec(ctx, restoreSpyCat, id)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const updateSpyCat = `-- name: UpdateSpyCat :execrows
+UPDATE spy_cats
+SET name = $1,
+    years_of_experience = $2,
+    breed = $3,
+    salary = $4,
+    salary_currency = $5
+WHERE id = $6
+  AND salary = $7
+  AND salary_currency = $8
 `
 
 type UpdateSpyCatParams struct {
-	ID     int64
-	Salary float64
+	Name              string
+	YearsOfExperience int32
+	Breed             string
+	Salary            int64
+	SalaryCurrency    string
+	ID                int64
+	OldSalary         int64
+	OldSalaryCurrency string
 }
 
-func (q *Queries) UpdateSpyCat(ctx context.Context, arg UpdateSpyCatParams) error {
-	_, err := q.db.Exec(ctx, updateSpyCat, arg.ID, arg.Salary)
-	return err
+func (q *Queries) UpdateSpyCat(ctx context.Context, arg UpdateSpyCatParams) (int64, error) {
+	result, err := q.db.Exec(ctx, updateSpyCat,
+		arg.Name,
+		arg.YearsOfExperience,
+		arg.Breed,
+		arg.Salary,
+		arg.SalaryCurrency,
+		arg.ID,
+		arg.OldSalary,
+		arg.OldSalaryCurrency,
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const updateSpyCatPassword = `-- name: UpdateSpyCatPassword :execrows
+UPDATE spy_cats
+SET password_hash = $2
+WHERE id = $1
+  AND deleted_at IS NULL
+`
+
+type UpdateSpyCatPasswordParams struct {
+	ID           int64
+	PasswordHash []byte
+}
+
+func (q *Queries) UpdateSpyCatPassword(ctx context.Context, arg UpdateSpyCatPasswordParams) (int64, error) {
+	result, err := q.db.Exec(ctx, updateSpyCatPassword, arg.ID, arg.PasswordHash)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
 }
@@ -11,6 +11,34 @@ import (
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
+const consumeToken = `-- name: ConsumeToken :one
+DELETE
+FROM tokens
+WHERE hash = $1
+  AND scope = $2
+  AND expiry >= $3
+RETURNING hash, user_id, user_type, expiry, scope
+`
+
+type ConsumeTokenParams struct {
+	Hash   []byte
+	Scope  string
+	Expiry pgtype.Timestamptz
+}
+
+func (q *Queries) ConsumeToken(ctx context.Context, arg ConsumeTokenParams) (Token, error) {
+	row := q.db.QueryRow(ctx, consumeToken, arg.Hash, arg.Scope, arg.Expiry)
+	var i Token
+	err := row.Scan(
+		&i.Hash,
+		&i.UserID,
+		&i.UserType,
+		&i.Expiry,
+		&i.Scope,
+	)
+	return i, err
+}
+
 const createToken = `-- name: CreateToken :exec
 INSERT INTO tokens (
   hash,
@@ -42,6 +70,58 @@ func (q *Queries) CreateToken(ctx context.Context, arg CreateTokenParams) error
 	return err
 }
 
+const deleteAllTokensForUser = `-- name: DeleteAllTokensForUser :exec
+DELETE
+FROM tokens
+WHERE user_id = $1
+  AND user_type = $2
+`
+
+type DeleteAllTokensForUserParams struct {
+	UserID   int64
+	UserType string
+}
+
+func (q *Queries) DeleteAllTokensForUser(ctx context.Context, arg DeleteAllTokensForUserParams) error {
+	_, err := q.db.Exec(ctx, deleteAllTokensForUser, arg.UserID, arg.UserType)
+	return err
+}
+
+const deleteExpiredTokens = `-- name: DeleteExpiredTokens :execrows
+DELETE
+FROM tokens
+WHERE hash IN (
+  SELECT hash
+  FROM tokens
+  WHERE expiry < $1
+  LIMIT $2
+)
+`
+
+type DeleteExpiredTokensParams struct {
+	Expiry    pgtype.Timestamptz
+	BatchSize int32
+}
+
+func (q *Queries) DeleteExpiredTokens(ctx context.Context, arg DeleteExpiredTokensParams) (int64, error) {
+	result, err := q.db.Exec(ctx, deleteExpiredTokens, arg.Expiry, arg.BatchSize)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const deleteTokenByHash = `-- name: DeleteTokenByHash :exec
+DELETE
+FROM tokens
+WHERE hash = $1
+`
+
+func (q *Queries) DeleteTokenByHash(ctx context.Context, hash []byte) error {
+	_, err := q.db.Exec(ctx, deleteTokenByHash, hash)
+	return err
+}
+
 const findTokenByPlaintext = `-- name: FindTokenByPlaintext :one
 SELECT hash, user_id, user_type, expiry, scope
 FROM tokens
@@ -4,8 +4,11 @@ import (
 	"context"
 	"crypto/sha256"
 	"encoding/base32"
+	"errors"
+	"fmt"
 	"time"
 
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgtype"
 	"github.com/m1crogravity/spy-cat-agency/internal/model"
 	"github.com/m1crogravity/spy-cat-agency/internal/storage"
@@ -13,12 +16,14 @@ import (
 )
 
 type TokensRepository struct {
-	queries *sqlc.Queries
+	queries    *sqlc.Queries
+	connection Connection
 }
 
-func NewTokensRepository(conn sqlc.DBTX) *TokensRepository {
+func NewTokensRepository(conn Connection) *TokensRepository {
 	return &TokensRepository{
-		queries: sqlc.New(conn),
+		queries:    sqlc.New(conn),
+		connection: conn,
 	}
 }
 
@@ -52,3 +57,117 @@ func (r *TokensRepository) FindByPlaintext(ctx context.Context, tokenPLaintext s
 		Scope:     token.Scope,
 	}, nil
 }
+
+// RedeemInvitation consumes the invitation token and creates the invited
+// agent in one transaction, so the token is kept when the agent can't be
+// created.
+func (r *TokensRepository) RedeemInvitation(ctx context.Context, tokenPlaintext string, agent *model.Agent) error {
+	tx, err := r.connection.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	txQuery := r.queries.WithTx(tx)
+	_, err = consumeToken(ctx, txQuery, tokenPlaintext, model.ScopeInvitation)
+	if err != nil {
+		return err
+	}
+
+	err = createAgent(ctx, txQuery, agent)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+// RedeemPasswordReset consumes the password reset token, sets the password of
+// its user and revokes the rest of the user's tokens in one transaction.
+func (r *TokensRepository) RedeemPasswordReset(ctx context.Context, tokenPlaintext string, password model.Password) error {
+	tx, err := r.connection.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	txQuery := r.queries.WithTx(tx)
+	token, err := consumeToken(ctx, txQuery, tokenPlaintext, model.ScopePasswordReset)
+	if err != nil {
+		return err
+	}
+
+	var rows int64
+	switch token.UserType {
+	case model.SpyCatUserType:
+		rows, err = txQuery.UpdateSpyCatPassword(ctx, sqlc.UpdateSpyCatPasswordParams{
+			ID:           token.UserID,
+			PasswordHash: password.Hash,
+		})
+	case model.AgentUserType:
+		rows, err = txQuery.UpdateAgentPassword(ctx, sqlc.UpdateAgentPasswordParams{
+			ID:           token.UserID,
+			PasswordHash: password.Hash,
+		})
+	default:
+		err = fmt.Errorf("unsupported token user type %q", token.UserType)
+	}
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return storage.ErrorModelNotFound
+	}
+
+	err = txQuery.DeleteAllTokensForUser(ctx, sqlc.DeleteAllTokensForUserParams{
+		UserID:   token.UserID,
+		UserType: string(token.UserType),
+	})
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+func consumeToken(ctx context.Context, txQuery *sqlc.Queries, tokenPlaintext string, scope string) (*model.Token, error) {
+	tokenHash := sha256.Sum256([]byte(tokenPlaintext))
+	token, err := txQuery.ConsumeToken(ctx, sqlc.ConsumeTokenParams{
+		Hash:   tokenHash[:],
+		Scope:  scope,
+		Expiry: pgtype.Timestamptz{Time: time.Now(), Valid: true},
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, storage.ErrorModelNotFound
+		}
+		return nil, err
+	}
+
+	return &model.Token{
+		Plaintext: tokenPlaintext,
+		Hash:      token.Hash,
+		UserID:    token.UserID,
+		UserType:  model.UserType(token.UserType),
+		Expiry:    token.Expiry.Time,
+		Scope:     token.Scope,
+	}, nil
+}
+
+func (r *TokensRepository) DeleteByHash(ctx context.Context, hash []byte) error {
+	return r.queries.DeleteTokenByHash(ctx, hash)
+}
+
+func (r *TokensRepository) DeleteAllForUser(ctx context.Context, userID int64, userType model.UserType) error {
+	return r.queries.DeleteAllTokensForUser(ctx, sqlc.DeleteAllTokensForUserParams{
+		UserID:   userID,
+		UserType: string(userType),
+	})
+}
+
+func (r *TokensRepository) DeleteExpired(ctx context.Context, before time.Time, limit int) (int64, error) {
+	return r.queries.DeleteExpiredTokens(ctx, sqlc.DeleteExpiredTokensParams{
+		Expiry:    pgtype.Timestamptz{Time: before, Valid: true},
+		BatchSize: int32(limit),
+	})
+}
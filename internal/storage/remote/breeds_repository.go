@@ -6,84 +6,230 @@ import (
 	"crypto/md5"
 	"encoding/json"
 	"errors"
+	"log/slog"
 	"net/http"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
+
+	"github.com/m1crogravity/spy-cat-agency/internal/model"
+	"github.com/m1crogravity/spy-cat-agency/internal/requestid"
+	"golang.org/x/sync/singleflight"
 )
 
-const breedUrl = "https://api.thecatapi.com/v1/breeds"
+const (
+	breedsPath = "/v1/breeds"
+
+	// refreshTimeout bounds a refresh which runs in the background, after the
+	// request which triggered it may already be gone.
+	refreshTimeout = 10 * time.Second
+	// retryInterval is the minimum time between refreshes while the API keeps
+	// failing and a stale list is being served.
+	retryInterval = 30 * time.Second
+)
+
+var ErrBreedsUnavailable = errors.New("Breed external API error")
 
 type Client interface {
 	Do(*http.Request) (*http.Response, error)
 }
 
+// BreedsStore persists the last good breed list, so it survives restarts and
+// outages of the API.
+type BreedsStore interface {
+	FindAll(context.Context) ([]model.Breed, error)
+	ReplaceAll(context.Context, []model.Breed) error
+}
+
+// BreedsStats counts cache lookups and outcomes of calls to the breeds API.
+type BreedsStats struct {
+	CacheHits         uint64
+	CacheStaleHits    uint64
+	CacheMisses       uint64
+	RequestsSucceeded uint64
+	RequestsFailed    uint64
+	UnexpectedStatus  uint64
+	StoreFallbacks    uint64
+}
+
+// BreedsRepository caches the breed list of TheCatAPI. A list older than the
+// TTL is still served while a single refresh runs in the background. When the
+// API is down and nothing is cached, the list persisted in the store is used.
 type BreedsRepository struct {
 	client   Client
-	cache    []string
+	store    BreedsStore
+	logger   *slog.Logger
+	url      string
 	cacheTtl time.Duration
-	cachedAt time.Time
-	cacheSum [16]byte
+
+	mu            sync.RWMutex
+	cache         []model.Breed
+	cachedAt      time.Time
+	cacheSum      [16]byte
+	lastAttemptAt time.Time
+
+	refresh singleflight.Group
+
+	cacheHits         atomic.Uint64
+	cacheStaleHits    atomic.Uint64
+	cacheMisses       atomic.Uint64
+	requestsSucceeded atomic.Uint64
+	requestsFailed    atomic.Uint64
+	unexpectedStatus  atomic.Uint64
+	storeFallbacks    atomic.Uint64
 }
 
-func NewBreedsRepository(client Client, cacheTtl time.Duration) *BreedsRepository {
+func NewBreedsRepository(client Client, store BreedsStore, logger *slog.Logger, baseUrl string, cacheTtl time.Duration) *BreedsRepository {
 	return &BreedsRepository{
 		client:   client,
+		store:    store,
+		logger:   logger,
+		url:      strings.TrimSuffix(baseUrl, "/") + breedsPath,
 		cacheTtl: cacheTtl,
 	}
 }
 
-func (r *BreedsRepository) FindAll(ctx context.Context) ([]string, error) {
-	if r.cache == nil || time.Since(r.cachedAt) > r.cacheTtl {
-		err := r.requestBreeds(ctx)
+func (r *BreedsRepository) FindAll(ctx context.Context) ([]model.Breed, error) {
+	r.mu.RLock()
+	cache, cachedAt, lastAttemptAt := r.cache, r.cachedAt, r.lastAttemptAt
+	r.mu.RUnlock()
+
+	switch {
+	case cache != nil && time.Since(cachedAt) <= r.cacheTtl:
+		r.cacheHits.Add(1)
+		return cache, nil
+	case cache != nil:
+		r.cacheStaleHits.Add(1)
+		if time.Since(lastAttemptAt) > retryInterval {
+			r.refreshInBackground(ctx)
+		}
+		return cache, nil
+	}
+
+	r.cacheMisses.Add(1)
+	ch := r.refresh.DoChan("breeds", func() (any, error) {
+		return r.load(context.WithoutCancel(ctx))
+	})
+
+	select {
+	case res := <-ch:
+		if res.Err != nil {
+			return nil, res.Err
+		}
+		return res.Val.([]model.Breed), nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+func (r *BreedsRepository) Stats() BreedsStats {
+	return BreedsStats{
+		CacheHits:         r.cacheHits.Load(),
+		CacheStaleHits:    r.cacheStaleHits.Load(),
+		CacheMisses:       r.cacheMisses.Load(),
+		RequestsSucceeded: r.requestsSucceeded.Load(),
+		RequestsFailed:    r.requestsFailed.Load(),
+		UnexpectedStatus:  r.unexpectedStatus.Load(),
+		StoreFallbacks:    r.storeFallbacks.Load(),
+	}
+}
+
+func (r *BreedsRepository) refreshInBackground(ctx context.Context) {
+	r.refresh.DoChan("breeds", func() (any, error) {
+		ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), refreshTimeout)
+		defer cancel()
+
+		breeds, err := r.requestBreeds(ctx)
 		if err != nil {
-			return nil, err
+			r.logger.WarnContext(ctx, "breeds refresh failed, serving stale list", "error", err)
 		}
+		return breeds, err
+	})
+}
+
+// load fills the empty cache from the API, or from the store when the API
+// fails. A list from the store is considered stale, so the API is tried again
+// on the next lookup after retryInterval.
+func (r *BreedsRepository) load(ctx context.Context) ([]model.Breed, error) {
+	breeds, err := r.requestBreeds(ctx)
+	if err == nil {
+		return breeds, nil
+	}
+
+	stored, storeErr := r.store.FindAll(ctx)
+	if storeErr != nil || len(stored) == 0 {
+		return nil, errors.Join(err, storeErr)
 	}
 
-	return r.cache, nil
+	r.storeFallbacks.Add(1)
+	r.logger.WarnContext(ctx, "breeds API unavailable, serving persisted list", "error", err)
+
+	r.mu.Lock()
+	r.cache = stored
+	r.mu.Unlock()
+
+	return stored, nil
 }
 
-func (r *BreedsRepository) requestBreeds(ctx context.Context) error {
-	req, err := http.NewRequestWithContext(ctx, "GET", breedUrl, nil)
+func (r *BreedsRepository) requestBreeds(ctx context.Context) ([]model.Breed, error) {
+	r.mu.Lock()
+	r.lastAttemptAt = time.Now()
+	r.mu.Unlock()
+
+	req, err := http.NewRequestWithContext(ctx, "GET", r.url, nil)
 	if err != nil {
-		return err
+		return nil, err
+	}
+	if id, ok := requestid.FromContext(ctx); ok {
+		req.Header.Set(requestid.Header, id)
 	}
 
 	resp, err := r.client.Do(req)
 	if err != nil {
-		return err
+		r.requestsFailed.Add(1)
+		return nil, err
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		return errors.New("Breed external API error")
+		r.unexpectedStatus.Add(1)
+		return nil, ErrBreedsUnavailable
 	}
+	r.requestsSucceeded.Add(1)
 
 	var buf bytes.Buffer
 	if _, err := buf.ReadFrom(resp.Body); err != nil {
-		return err
+		return nil, err
 	}
 	hash := md5.Sum(buf.Bytes())
-	if hash == r.cacheSum {
-		r.cachedAt = time.Now()
-		return nil
-	}
 
-	var breeds []struct {
-		Name string `json:"name"`
-	}
+	r.mu.RLock()
+	cache, unchanged := r.cache, hash == r.cacheSum
+	r.mu.RUnlock()
 
-	if err := json.NewDecoder(&buf).Decode(&breeds); err != nil {
-		return err
+	if unchanged {
+		r.mu.Lock()
+		r.cachedAt = time.Now()
+		r.mu.Unlock()
+		return cache, nil
 	}
 
-	names := make([]string, len(breeds))
-	for i, breed := range breeds {
-		names[i] = breed.Name
+	var breeds []model.Breed
+	if err := json.NewDecoder(&buf).Decode(&breeds); err != nil {
+		return nil, err
 	}
 
-	r.cache = names
+	r.mu.Lock()
+	r.cache = breeds
 	r.cachedAt = time.Now()
 	r.cacheSum = hash
+	r.mu.Unlock()
+
+	err = r.store.ReplaceAll(ctx, breeds)
+	if err != nil {
+		r.logger.ErrorContext(ctx, "persisting breeds failed", "error", err)
+	}
 
-	return nil
+	return breeds, nil
 }
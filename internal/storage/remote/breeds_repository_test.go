@@ -0,0 +1,169 @@
+package remote
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/m1crogravity/spy-cat-agency/internal/model"
+)
+
+type breedsStore struct {
+	mu     sync.Mutex
+	breeds []model.Breed
+}
+
+func (s *breedsStore) FindAll(ctx context.Context) ([]model.Breed, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.breeds, nil
+}
+
+func (s *breedsStore) ReplaceAll(ctx context.Context, breeds []model.Breed) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.breeds = breeds
+	return nil
+}
+
+type breedsApi struct {
+	*httptest.Server
+	calls atomic.Int64
+	down  atomic.Bool
+	body  atomic.Value
+}
+
+func newBreedsApi(t *testing.T, body string) *breedsApi {
+	api := &breedsApi{}
+	api.body.Store(body)
+	api.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		api.calls.Add(1)
+		if r.URL.Path != breedsPath || api.down.Load() {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		io.WriteString(w, api.body.Load().(string))
+	}))
+	t.Cleanup(api.Close)
+
+	return api
+}
+
+func newTestBreedsRepository(api *breedsApi, store BreedsStore, ttl time.Duration) *BreedsRepository {
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	return NewBreedsRepository(http.DefaultClient, store, logger, api.URL, ttl)
+}
+
+func TestBreedsFindAllConcurrent(t *testing.T) {
+	api := newBreedsApi(t, `[{"id": "abys", "name": "Abyssinian", "origin": "Egypt", "temperament": "Active, Energetic", "life_span": "14 - 15"}, {"id": "beng", "name": "Bengal"}]`)
+	want := []model.Breed{
+		{Id: "abys", Name: "Abyssinian", Origin: "Egypt", Temperament: "Active, Energetic", LifeSpan: "14 - 15"},
+		{Id: "beng", Name: "Bengal"},
+	}
+	store := &breedsStore{}
+	repo := newTestBreedsRepository(api, store, time.Minute)
+
+	var wg sync.WaitGroup
+	for range 20 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			breeds, err := repo.FindAll(t.Context())
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			if !slices.Equal(breeds, want) {
+				t.Errorf("unexpected breeds %v", breeds)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if calls := api.calls.Load(); calls != 1 {
+		t.Fatalf("Expected 1 call to the API, got %d", calls)
+	}
+
+	stored, _ := store.FindAll(t.Context())
+	if !slices.Equal(stored, want) {
+		t.Fatalf("Expected breeds to be persisted, got %v", stored)
+	}
+}
+
+func TestBreedsFindAllStaleWhileRevalidate(t *testing.T) {
+	api := newBreedsApi(t, `[{"name": "Abyssinian"}]`)
+	repo := newTestBreedsRepository(api, &breedsStore{}, time.Nanosecond)
+
+	_, err := repo.FindAll(t.Context())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	api.down.Store(true)
+	repo.mu.Lock()
+	repo.lastAttemptAt = time.Time{}
+	repo.mu.Unlock()
+
+	breeds, err := repo.FindAll(t.Context())
+	if err != nil {
+		t.Fatal("Expected stale breeds to be served while the API is down")
+	}
+	if !slices.Equal(model.BreedNames(breeds), []string{"Abyssinian"}) {
+		t.Fatalf("unexpected breeds %v", breeds)
+	}
+
+	api.body.Store(`[{"name": "Bengal"}]`)
+	api.down.Store(false)
+	repo.mu.Lock()
+	repo.lastAttemptAt = time.Time{}
+	repo.mu.Unlock()
+
+	_, err = repo.FindAll(t.Context())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	deadline := time.Now().Add(time.Second)
+	for {
+		repo.mu.RLock()
+		cache := repo.cache
+		repo.mu.RUnlock()
+		if slices.Equal(model.BreedNames(cache), []string{"Bengal"}) {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("Expected cache to be refreshed in the background, got %v", cache)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestBreedsFindAllStoreFallback(t *testing.T) {
+	api := newBreedsApi(t, "")
+	api.down.Store(true)
+
+	repo := newTestBreedsRepository(api, &breedsStore{}, time.Minute)
+	_, err := repo.FindAll(t.Context())
+	if err == nil {
+		t.Fatal("Expected error when neither the API nor the store has breeds")
+	}
+
+	repo = newTestBreedsRepository(api, &breedsStore{breeds: []model.Breed{{Name: "Bengal"}}}, time.Minute)
+	breeds, err := repo.FindAll(t.Context())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(model.BreedNames(breeds), []string{"Bengal"}) {
+		t.Fatalf("Expected persisted breeds, got %v", breeds)
+	}
+	if repo.Stats().StoreFallbacks != 1 {
+		t.Fatal("Expected store fallback to be counted")
+	}
+}
@@ -0,0 +1,2 @@
+ALTER TABLE agents DROP CONSTRAINT IF EXISTS agents_role_check;
+ALTER TABLE agents DROP COLUMN IF EXISTS role;
@@ -0,0 +1,3 @@
+ALTER TABLE agents ADD COLUMN IF NOT EXISTS role text NOT NULL DEFAULT 'admin';
+ALTER TABLE agents ALTER COLUMN role SET DEFAULT 'analyst';
+ALTER TABLE agents ADD CONSTRAINT agents_role_check CHECK (role IN ('admin', 'handler', 'analyst'));
@@ -0,0 +1,8 @@
+CREATE TABLE IF NOT EXISTS lockouts (
+  user_type text NOT NULL,
+  name text NOT NULL,
+  failures integer NOT NULL DEFAULT 0,
+  locked_until timestamp(0) with time zone,
+  last_failure_at timestamp(0) with time zone NOT NULL,
+  PRIMARY KEY (user_type, name)
+);
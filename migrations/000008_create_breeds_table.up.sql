@@ -0,0 +1,3 @@
+CREATE TABLE IF NOT EXISTS breeds (
+  name text PRIMARY KEY
+);
@@ -0,0 +1,4 @@
+ALTER TABLE breeds DROP COLUMN IF EXISTS life_span;
+ALTER TABLE breeds DROP COLUMN IF EXISTS temperament;
+ALTER TABLE breeds DROP COLUMN IF EXISTS origin;
+ALTER TABLE breeds DROP COLUMN IF EXISTS id;
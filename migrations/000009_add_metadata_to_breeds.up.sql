@@ -0,0 +1,4 @@
+ALTER TABLE breeds ADD COLUMN IF NOT EXISTS id text NOT NULL DEFAULT '';
+ALTER TABLE breeds ADD COLUMN IF NOT EXISTS origin text NOT NULL DEFAULT '';
+ALTER TABLE breeds ADD COLUMN IF NOT EXISTS temperament text NOT NULL DEFAULT '';
+ALTER TABLE breeds ADD COLUMN IF NOT EXISTS life_span text NOT NULL DEFAULT '';
@@ -0,0 +1,12 @@
+CREATE TABLE IF NOT EXISTS salary_changes (
+  id bigserial PRIMARY KEY,
+  spy_cat_id bigint NOT NULL REFERENCES spy_cats ON DELETE CASCADE,
+  agent_id bigint NOT NULL REFERENCES agents,
+  old_salary double precision NOT NULL,
+  new_salary double precision NOT NULL,
+  effective_date date NOT NULL,
+  reason text NOT NULL,
+  created_at timestamp(0) with time zone NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS salary_changes_spy_cat_id_idx ON salary_changes (spy_cat_id, effective_date);
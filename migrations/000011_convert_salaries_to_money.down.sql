@@ -0,0 +1,7 @@
+-- Like the up migration, this assumes 2 minor unit digits for every salary.
+ALTER TABLE salary_changes DROP COLUMN IF EXISTS new_salary_currency;
+ALTER TABLE salary_changes DROP COLUMN IF EXISTS old_salary_currency;
+ALTER TABLE salary_changes ALTER COLUMN new_salary TYPE double precision USING new_salary / 100.0;
+ALTER TABLE salary_changes ALTER COLUMN old_salary TYPE double precision USING old_salary / 100.0;
+ALTER TABLE spy_cats DROP COLUMN IF EXISTS salary_currency;
+ALTER TABLE spy_cats ALTER COLUMN salary TYPE double precision USING salary / 100.0;
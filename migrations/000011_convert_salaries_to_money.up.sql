@@ -0,0 +1,10 @@
+-- Salaries used to be plain numbers without a currency. All of them are taken
+-- to be USD and stored in cents, i.e. with the 2 minor unit digits of USD. This
+-- doesn't hold for every currency (JPY has none), so any later conversion has
+-- to use the minor unit digits of the currency as defined in internal/model.
+ALTER TABLE spy_cats ALTER COLUMN salary TYPE bigint USING round(salary * 100)::bigint;
+ALTER TABLE spy_cats ADD COLUMN IF NOT EXISTS salary_currency text NOT NULL DEFAULT 'USD';
+ALTER TABLE salary_changes ALTER COLUMN old_salary TYPE bigint USING round(old_salary * 100)::bigint;
+ALTER TABLE salary_changes ALTER COLUMN new_salary TYPE bigint USING round(new_salary * 100)::bigint;
+ALTER TABLE salary_changes ADD COLUMN IF NOT EXISTS old_salary_currency text NOT NULL DEFAULT 'USD';
+ALTER TABLE salary_changes ADD COLUMN IF NOT EXISTS new_salary_currency text NOT NULL DEFAULT 'USD';
@@ -0,0 +1 @@
+ALTER TABLE spy_cats DROP COLUMN IF EXISTS deleted_at;
@@ -0,0 +1 @@
+ALTER TABLE spy_cats ADD COLUMN IF NOT EXISTS deleted_at timestamp(0) with time zone NULL;
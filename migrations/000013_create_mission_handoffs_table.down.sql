@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS missions_in_progress_spy_cat_id_idx;
+DROP TABLE IF EXISTS mission_handoffs;
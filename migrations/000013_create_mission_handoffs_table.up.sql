@@ -0,0 +1,12 @@
+CREATE TABLE IF NOT EXISTS mission_handoffs (
+  id bigserial PRIMARY KEY,
+  mission_id bigint NOT NULL REFERENCES missions ON DELETE CASCADE,
+  from_spy_cat_id bigint NOT NULL REFERENCES spy_cats ON DELETE CASCADE,
+  to_spy_cat_id bigint NULL REFERENCES spy_cats ON DELETE CASCADE,
+  agent_id bigint NOT NULL REFERENCES agents,
+  created_at timestamp(0) with time zone NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS mission_handoffs_mission_id_idx ON mission_handoffs (mission_id);
+
+CREATE UNIQUE INDEX IF NOT EXISTS missions_in_progress_spy_cat_id_idx ON missions (spy_cat_id) WHERE state = 'in_progress';
@@ -0,0 +1 @@
+ALTER TABLE missions ADD COLUMN IF NOT EXISTS reason text NOT NULL DEFAULT '';
@@ -0,0 +1 @@
+UPDATE tokens SET user_type = 'agent' WHERE scope = 'invitation' AND user_type = 'invitation';
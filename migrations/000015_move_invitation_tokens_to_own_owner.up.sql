@@ -0,0 +1 @@
+UPDATE tokens SET user_type = 'invitation' WHERE scope = 'invitation' AND user_type = 'agent';
@@ -0,0 +1,13 @@
+ALTER TABLE spy_cats ADD COLUMN IF NOT EXISTS created_at timestamp(0) with time zone NOT NULL DEFAULT NOW();
+
+-- The hiring date of existing spy cats isn't known. The earliest entry of the
+-- salary history is the best bound there is, the others count from now on.
+UPDATE spy_cats
+SET created_at = history.first_at
+FROM (
+  SELECT spy_cat_id, LEAST(min(effective_date)::timestamptz, min(created_at)) AS first_at
+  FROM salary_changes
+  GROUP BY spy_cat_id
+) AS history
+WHERE history.spy_cat_id = spy_cats.id
+  AND history.first_at < spy_cats.created_at;
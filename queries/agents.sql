@@ -1,8 +1,8 @@
 -- name: CreateAgent :one
 INSERT INTO agents (
-  name, password_hash
+  name, password_hash, role
 ) VALUES (
-  $1, $2
+  $1, $2, $3
 )
 RETURNING id;
 
@@ -17,3 +17,20 @@ SELECT *
 FROM agents
 WHERE id = $1
 LIMIT 1;
+
+-- name: UpdateAgentRole :exec
+UPDATE agents
+SET role = $2
+WHERE id = $1;
+
+-- name: CountAgents :one
+SELECT count(*)
+FROM agents;
+
+-- name: LockAgents :exec
+LOCK TABLE agents IN SHARE ROW EXCLUSIVE MODE;
+
+-- name: UpdateAgentPassword :execrows
+UPDATE agents
+SET password_hash = $2
+WHERE id = $1;
@@ -0,0 +1,15 @@
+-- name: ListBreeds :many
+SELECT *
+FROM breeds
+ORDER BY name;
+
+-- name: DeleteAllBreeds :exec
+DELETE
+FROM breeds;
+
+-- name: CreateBreeds :copyfrom
+INSERT INTO breeds (
+  name, id, origin, temperament, life_span
+) VALUES (
+  $1, $2, $3, $4, $5
+);
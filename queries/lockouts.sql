@@ -0,0 +1,47 @@
+-- name: ListLockedLockouts :many
+SELECT *
+FROM lockouts
+WHERE locked_until > $1
+ORDER BY locked_until DESC;
+
+-- name: CreateLockout :exec
+INSERT INTO lockouts (
+  user_type,
+  name,
+  last_failure_at
+) VALUES (
+  $1, $2, $3
+)
+ON CONFLICT (user_type, name) DO NOTHING;
+
+-- name: FindLockoutForUpdate :one
+SELECT *
+FROM lockouts
+WHERE user_type = $1
+  AND name = $2
+FOR UPDATE;
+
+-- name: UpdateLockout :exec
+UPDATE lockouts
+SET failures = $3,
+  locked_until = $4,
+  last_failure_at = $5
+WHERE user_type = $1
+  AND name = $2;
+
+-- name: DeleteLockout :execrows
+DELETE
+FROM lockouts
+WHERE user_type = $1
+  AND name = $2;
+
+-- name: DeleteStaleLockouts :execrows
+DELETE
+FROM lockouts
+WHERE (user_type, name) IN (
+  SELECT user_type, name
+  FROM lockouts
+  WHERE last_failure_at < @reset_before
+    AND (locked_until IS NULL OR locked_until <= @now)
+  LIMIT @batch_size
+);
@@ -0,0 +1,16 @@
+-- name: CreateMissionHandoff :one
+INSERT INTO mission_handoffs (
+  mission_id,
+  from_spy_cat_id,
+  to_spy_cat_id,
+  agent_id
+) VALUES (
+  $1, $2, $3, $4
+)
+RETURNING id, created_at;
+
+-- name: ListMissionHandoffs :many
+SELECT *
+FROM mission_handoffs
+WHERE mission_id = $1
+ORDER BY id;
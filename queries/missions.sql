@@ -20,6 +20,7 @@ INSERT INTO targets (
 -- name: FindMissionById :many
 SELECT missions.id as mission_id,
   missions.state as mission_state,
+  missions.reason as mission_reason,
   missions.spy_cat_id,
   targets.id as target_id,
   targets.name,
@@ -35,11 +36,19 @@ DELETE
 FROM missions
 WHERE id = $1;
 
--- name: UpdateMission :exec
+-- name: LockMission :one
+SELECT spy_cat_id
+FROM missions
+WHERE id = $1
+FOR UPDATE;
+
+-- name: UpdateMission :execrows
 UPDATE missions
-SET state = $2,
-  spy_cat_id = $3
-WHERE id = $1;
+SET state = @state,
+  spy_cat_id = @spy_cat_id,
+  reason = @reason
+WHERE id = @id
+  AND state = @from_state;
 
 -- name: UpdateTarget :exec
 UPDATE targets
@@ -57,6 +66,7 @@ WHERE id = $1
 -- name: FindActiveMission :many
 SELECT missions.id as mission_id,
   missions.state as mission_state,
+  missions.reason as mission_reason,
   missions.spy_cat_id,
   targets.id as target_id,
   targets.name,
@@ -68,9 +78,10 @@ INNER JOIN targets ON missions.id = targets.mission_id
 WHERE missions.spy_cat_id = $1
   AND missions.state = 'in_progress';
 
--- name: FindAllMissions :many
+-- name: ListMissions :many
 SELECT missions.id as mission_id,
   missions.state as mission_state,
+  missions.reason as mission_reason,
   missions.spy_cat_id,
   targets.id as target_id,
   targets.name,
@@ -79,4 +90,24 @@ SELECT missions.id as mission_id,
   targets.state as target_state
 FROM missions
 INNER JOIN targets ON missions.id = targets.mission_id
-ORDER BY missions.id ASC;
+WHERE missions.id IN (
+  SELECT m.id
+  FROM missions m
+  WHERE m.id > @after
+    AND (sqlc.narg('state')::text IS NULL OR m.state = sqlc.narg('state'))
+    AND (sqlc.narg('spy_cat_id')::bigint IS NULL OR m.spy_cat_id = sqlc.narg('spy_cat_id'))
+    AND (sqlc.narg('country')::text IS NULL OR EXISTS (
+      SELECT 1
+      FROM targets t
+      WHERE t.mission_id = m.id
+        AND lower(t.country) = lower(sqlc.narg('country'))
+    ))
+  ORDER BY m.id ASC
+  LIMIT @page_limit
+)
+ORDER BY missions.id ASC, targets.id ASC;
+
+-- name: CountMissionsByState :many
+SELECT state, count(*) AS count
+FROM missions
+GROUP BY state;
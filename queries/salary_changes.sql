@@ -0,0 +1,43 @@
+-- name: CreateSalaryChange :one
+INSERT INTO salary_changes (
+  spy_cat_id,
+  agent_id,
+  old_salary,
+  old_salary_currency,
+  new_salary,
+  new_salary_currency,
+  effective_date,
+  reason
+) VALUES (
+  $1, $2, $3, $4, $5, $6, $7, $8
+)
+RETURNING id, created_at;
+
+-- name: ListSalaryChangesBySpyCat :many
+SELECT *
+FROM salary_changes
+WHERE spy_cat_id = $1
+ORDER BY effective_date, id;
+
+-- name: ListSalaryChangesBetween :many
+SELECT *
+FROM salary_changes
+WHERE effective_date > @after::date
+  AND (
+    effective_date <= @until::date
+    OR id IN (
+      SELECT DISTINCT ON (spy_cat_id) id
+      FROM salary_changes
+      WHERE effective_date > @until::date
+      ORDER BY spy_cat_id, effective_date, id
+    )
+  )
+ORDER BY spy_cat_id, effective_date, id;
+
+-- name: SalaryChangeExistsAfter :one
+SELECT EXISTS (
+  SELECT 1
+  FROM salary_changes
+  WHERE spy_cat_id = $1
+    AND effective_date > $2
+);
@@ -4,11 +4,12 @@ INSERT INTO spy_cats (
   password_hash,
   years_of_experience,
   breed,
-  salary
+  salary,
+  salary_currency
 ) VALUES (
-  $1, $2, $3, $4, $5
+  $1, $2, $3, $4, $5, $6
 )
-RETURNING id;
+RETURNING id, created_at;
 
 -- name: FindSpyCatById :one
 SELECT *
@@ -16,20 +17,80 @@ FROM spy_cats
 WHERE id = $1
 LIMIT 1;
 
--- name: DeleteSpyCatById :exec
-DELETE
+-- name: LockSpyCat :one
+SELECT id
 FROM spy_cats
-WHERE id = $1;
+WHERE id = $1
+  AND deleted_at IS NULL
+FOR UPDATE;
+
+-- name: ArchiveSpyCat :execrows
+UPDATE spy_cats
+SET deleted_at = NOW()
+WHERE id = $1
+  AND deleted_at IS NULL
+  AND NOT EXISTS (
+    SELECT 1
+    FROM missions
+    WHERE spy_cat_id = $1
+      AND state = 'in_progress'
+  );
+
+-- name: RestoreSpyCat :execrows
+UPDATE spy_cats
+SET deleted_at = NULL
+WHERE id = $1
+  AND deleted_at IS NOT NULL;
+
+-- name: UpdateSpyCat :execrows
+UPDATE spy_cats
+SET name = @name,
+    years_of_experience = @years_of_experience,
+    breed = @breed,
+    salary = @salary,
+    salary_currency = @salary_currency
+WHERE id = @id
+  AND salary = @old_salary
+  AND salary_currency = @old_salary_currency;
 
--- name: UpdateSpyCat :exec
+-- name: UpdateSpyCatPassword :execrows
 UPDATE spy_cats
-SET salary = $2
-WHERE id = $1;
+SET password_hash = $2
+WHERE id = $1
+  AND deleted_at IS NULL;
+
+-- name: CountSpyCats :one
+SELECT count(*)
+FROM spy_cats
+WHERE (sqlc.narg('breed')::text IS NULL OR lower(breed) = lower(sqlc.narg('breed')))
+  AND (sqlc.narg('min_experience')::integer IS NULL OR years_of_experience >= sqlc.narg('min_experience'))
+  AND (sqlc.narg('max_experience')::integer IS NULL OR years_of_experience <= sqlc.narg('max_experience'))
+  AND (sqlc.narg('salary_currency')::text IS NULL OR salary_currency = sqlc.narg('salary_currency'))
+  AND (sqlc.narg('min_salary')::bigint IS NULL OR salary >= sqlc.narg('min_salary'))
+  AND (sqlc.narg('max_salary')::bigint IS NULL OR salary <= sqlc.narg('max_salary'))
+  AND (@status::text = 'all' OR (@status::text = 'archived') = (deleted_at IS NOT NULL));
 
 -- name: ListSpyCats :many
 SELECT *
 FROM spy_cats
-ORDER BY id ASC;
+WHERE (sqlc.narg('breed')::text IS NULL OR lower(breed) = lower(sqlc.narg('breed')))
+  AND (sqlc.narg('min_experience')::integer IS NULL OR years_of_experience >= sqlc.narg('min_experience'))
+  AND (sqlc.narg('max_experience')::integer IS NULL OR years_of_experience <= sqlc.narg('max_experience'))
+  AND (sqlc.narg('salary_currency')::text IS NULL OR salary_currency = sqlc.narg('salary_currency'))
+  AND (sqlc.narg('min_salary')::bigint IS NULL OR salary >= sqlc.narg('min_salary'))
+  AND (sqlc.narg('max_salary')::bigint IS NULL OR salary <= sqlc.narg('max_salary'))
+  AND (@status::text = 'all' OR (@status::text = 'archived') = (deleted_at IS NOT NULL))
+ORDER BY
+  CASE WHEN @sort::text = 'name' THEN name END ASC,
+  CASE WHEN @sort::text = '-name' THEN name END DESC,
+  CASE WHEN @sort::text = 'years_of_experience' THEN years_of_experience END ASC,
+  CASE WHEN @sort::text = '-years_of_experience' THEN years_of_experience END DESC,
+  CASE WHEN @sort::text IN ('salary', '-salary') THEN salary_currency END ASC,
+  CASE WHEN @sort::text = 'salary' THEN salary END ASC,
+  CASE WHEN @sort::text = '-salary' THEN salary END DESC,
+  CASE WHEN @sort::text = '-id' THEN id END DESC,
+  id ASC
+LIMIT @page_limit OFFSET @page_offset;
 
 -- name: FindSpyCatByName :one
 SELECT *
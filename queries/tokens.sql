@@ -15,3 +15,32 @@ FROM tokens
 WHERE hash = $1
   AND scope = $2
   AND expiry >= $3;
+
+-- name: ConsumeToken :one
+DELETE
+FROM tokens
+WHERE hash = $1
+  AND scope = $2
+  AND expiry >= $3
+RETURNING *;
+
+-- name: DeleteTokenByHash :exec
+DELETE
+FROM tokens
+WHERE hash = $1;
+
+-- name: DeleteAllTokensForUser :exec
+DELETE
+FROM tokens
+WHERE user_id = $1
+  AND user_type = $2;
+
+-- name: DeleteExpiredTokens :execrows
+DELETE
+FROM tokens
+WHERE hash IN (
+  SELECT hash
+  FROM tokens
+  WHERE expiry < @expiry
+  LIMIT @batch_size
+);